@@ -0,0 +1,208 @@
+package column
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/filter"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Type is the type of the values a column is filtered with.
+type Type string
+
+const (
+	STRING  Type = "string"
+	NUMBER  Type = "number"
+	BOOLEAN Type = "boolean"
+	DATE    Type = "date"
+)
+
+// Config describes a filterable field of a model and how it maps to the database.
+type Config struct {
+	Field     string             // Name of the field used by the client, e.g. "country.name"
+	Column    string             // SQL column or expression the field maps to, e.g. "c.name"
+	Type      Type               // Type of the values of the field
+	Modes     []filter.MatchMode // Match modes allowed on the field; empty allows every registered match mode
+	Sortable  bool               // Whether the table can be sorted by the field
+	Label     string             // Display name of the field
+	Sensitive bool               // Whether the values of the field are redacted from debug output
+}
+
+// Configs holds the configuration of the fields of a model, keyed by field name.
+// It is a Validator that only allows the configured fields.
+type Configs map[string]Config
+
+// FromStruct derives the column configuration of a model from the prime struct tags of its fields.
+// Only fields with a prime tag are included. A tag holds the field name followed by options:
+//
+//	Name string `prime:"name,col=c.name,type=string,modes=contains|startsWith,sortable,label=Name"`
+//	SSN  string `prime:"ssn,sensitive"`
+//
+// Options:
+//
+//	col:       The SQL column or expression of the field; defaults to the field name.
+//	type:      The value type (string, number, boolean or date); defaults to the type of the Go field.
+//	modes:     The allowed match modes separated by "|", built-in or custom; defaults to every registered match mode.
+//	sortable:  Marks the field as sortable.
+//	label:     The display name of the field; defaults to the field name.
+//	sensitive: Redacts the values of the field from debug output, see prime.Filter.Debug.
+//
+// An empty name defaults to the name of the json tag, or to the name of the Go field.
+//
+// Returns:
+//
+//	The Configs of the model, or an error if T is not a struct or a tag is invalid.
+func FromStruct[T any]() (Configs, error) {
+	return Parse(reflect.TypeFor[T]())
+}
+
+// Parse derives the column configuration of a struct type from its prime struct tags, like FromStruct.
+// Parameters:
+//
+//	t: The struct type, or a pointer to it.
+//
+// Returns:
+//
+//	The Configs of the struct type, or an error if t is not a struct or a tag is invalid.
+func Parse(t reflect.Type) (Configs, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("column config requires a struct, got %s", t)
+	}
+
+	configs := make(Configs)
+	for _, f := range reflect.VisibleFields(t) {
+		tag, ok := f.Tag.Lookup("prime")
+		if !ok || tag == "-" || !f.IsExported() {
+			continue
+		}
+
+		config, err := parseTag(f, tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+		if _, exists := configs[config.Field]; exists {
+			return nil, fmt.Errorf("%s.%s: duplicate field [%s]", t.Name(), f.Name, config.Field)
+		}
+		configs[config.Field] = config
+	}
+	return configs, nil
+}
+
+func parseTag(f reflect.StructField, tag string) (Config, error) {
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name, _, _ = strings.Cut(f.Tag.Get("json"), ",")
+	}
+	if name == "" || name == "-" {
+		name = f.Name
+	}
+
+	config := Config{Field: name, Column: name, Type: typeOf(f.Type), Label: name}
+	for _, option := range strings.Split(options, ",") {
+		if option == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(option, "=")
+		switch strings.TrimSpace(key) {
+		case "col":
+			config.Column = value
+		case "type":
+			config.Type = Type(value)
+			if !config.Type.valid() {
+				return Config{}, fmt.Errorf("unknown type [%s]", value)
+			}
+		case "modes":
+			for _, mode := range strings.Split(value, "|") {
+				if mode == "" {
+					return Config{}, fmt.Errorf("empty match mode in [%s]", value)
+				}
+				config.Modes = append(config.Modes, filter.MatchMode(mode))
+			}
+		case "sortable":
+			config.Sortable = true
+		case "label":
+			config.Label = value
+		case "sensitive":
+			config.Sensitive = true
+		default:
+			return Config{}, fmt.Errorf("unknown option [%s]", key)
+		}
+	}
+
+	if config.Type == "" {
+		return Config{}, fmt.Errorf("cannot infer the type of field [%s] from %s, set the type option", name, f.Type)
+	}
+	return config, nil
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+func typeOf(t reflect.Type) Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return DATE
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return STRING
+	case reflect.Bool:
+		return BOOLEAN
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return NUMBER
+	}
+	return ""
+}
+
+// Modes returns the built-in match modes suited to the values of the type.
+func (t Type) Modes() []filter.MatchMode {
+	switch t {
+	case STRING:
+		return []filter.MatchMode{filter.EQUALS, filter.NOT_EQUALS, filter.CONTAINS, filter.NOT_CONTAINS,
+			filter.STARTS_WITH, filter.ENDS_WITH, filter.IN}
+	case NUMBER:
+		return []filter.MatchMode{filter.EQUALS, filter.NOT_EQUALS, filter.LESS_THAN, filter.LESS_THAN_EQUALS,
+			filter.GREATER_THAN, filter.GREATER_THAN_EQUALS, filter.IN, filter.BETWEEN}
+	case BOOLEAN:
+		return []filter.MatchMode{filter.EQUALS, filter.NOT_EQUALS}
+	case DATE:
+		return []filter.MatchMode{filter.DATE_IS, filter.DATE_IS_NOT, filter.DATE_BEFORE, filter.DATE_AFTER, filter.BETWEEN}
+	}
+	return nil
+}
+
+func (t Type) valid() bool {
+	switch t {
+	case STRING, NUMBER, BOOLEAN, DATE:
+		return true
+	}
+	return false
+}
+
+// Column returns the SQL column of a field, or the field itself if it is not configured.
+func (c Configs) Column(field string) string {
+	if config, ok := c[field]; ok && config.Column != "" {
+		return config.Column
+	}
+	return field
+}
+
+// Validate allows only the configured fields.
+func (c Configs) Validate(column string) error {
+	if _, ok := c[column]; ok {
+		return nil
+	}
+	return fmt.Errorf("column [%s] is not allowed", column)
+}
+
+func (c Configs) Name() string {
+	return "ConfigValidator"
+}
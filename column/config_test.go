@@ -0,0 +1,134 @@
+package column
+
+import (
+	"github.com/AdamShannag/goprime/filter"
+	"slices"
+	"testing"
+	"time"
+)
+
+type customer struct {
+	ID       int        `json:"id" prime:",modes=equals|in,sortable"`
+	Name     string     `prime:"name,col=c.name,type=string,modes=contains|startsWith,sortable,label=Customer name"`
+	Country  string     `prime:"country.name,col=co.name"`
+	Date     *time.Time `prime:"date,sortable"`
+	Verified bool       `json:"verified" prime:""`
+	SSN      string     `prime:"ssn,sensitive"`
+	Internal string     `prime:"-"`
+	Notes    string
+}
+
+func TestFromStruct(t *testing.T) {
+	configs, err := FromStruct[customer]()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := Configs{
+		"id":           {Field: "id", Column: "id", Type: NUMBER, Modes: []filter.MatchMode{filter.EQUALS, filter.IN}, Sortable: true, Label: "id"},
+		"name":         {Field: "name", Column: "c.name", Type: STRING, Modes: []filter.MatchMode{filter.CONTAINS, filter.STARTS_WITH}, Sortable: true, Label: "Customer name"},
+		"country.name": {Field: "country.name", Column: "co.name", Type: STRING, Label: "country.name"},
+		"date":         {Field: "date", Column: "date", Type: DATE, Sortable: true, Label: "date"},
+		"verified":     {Field: "verified", Column: "verified", Type: BOOLEAN, Label: "verified"},
+		"ssn":          {Field: "ssn", Column: "ssn", Type: STRING, Label: "ssn", Sensitive: true},
+	}
+
+	if len(configs) != len(expected) {
+		t.Fatalf("expected %d configs, got %d: %v", len(expected), len(configs), configs)
+	}
+
+	for field, e := range expected {
+		c, ok := configs[field]
+		if !ok {
+			t.Errorf("expected config for field %s", field)
+			continue
+		}
+		if c.Field != e.Field || c.Column != e.Column || c.Type != e.Type || c.Sortable != e.Sortable || c.Label != e.Label || c.Sensitive != e.Sensitive || !slices.Equal(c.Modes, e.Modes) {
+			t.Errorf("expected config %+v, got %+v", e, c)
+		}
+	}
+}
+
+func TestFromStructErrors(t *testing.T) {
+	type unknownOption struct {
+		Name string `prime:"name,indexed"`
+	}
+	type unknownType struct {
+		Name string `prime:"name,type=text"`
+	}
+	type uninferableType struct {
+		Tags []string `prime:"tags"`
+	}
+	type emptyMode struct {
+		Name string `prime:"name,modes=contains||startsWith"`
+	}
+	type emptyModes struct {
+		Name string `prime:"name,modes="`
+	}
+	type duplicateField struct {
+		Name  string `prime:"name"`
+		Other string `prime:"name"`
+	}
+
+	tests := []struct {
+		name string
+		fn   func() (Configs, error)
+	}{
+		{"not a struct", FromStruct[string]},
+		{"unknown option", FromStruct[unknownOption]},
+		{"unknown type", FromStruct[unknownType]},
+		{"uninferable type", FromStruct[uninferableType]},
+		{"empty match mode", FromStruct[emptyMode]},
+		{"empty match modes", FromStruct[emptyModes]},
+		{"duplicate field", FromStruct[duplicateField]},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.fn(); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestConfigs(t *testing.T) {
+	configs, err := FromStruct[*customer]()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if configs.Column("name") != "c.name" {
+		t.Errorf("expected column c.name, got %s", configs.Column("name"))
+	}
+
+	if configs.Column("unknown") != "unknown" {
+		t.Errorf("expected an unknown field to map to itself, got %s", configs.Column("unknown"))
+	}
+
+	if err := configs.Validate("country.name"); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if err := configs.Validate("Notes"); err == nil || err.Error() != "column [Notes] is not allowed" {
+		t.Errorf("expected column [Notes] is not allowed, got %v", err)
+	}
+
+	if configs.Name() != "ConfigValidator" {
+		t.Errorf("expected validator name ConfigValidator, got %s", configs.Name())
+	}
+}
+
+func TestTypeModes(t *testing.T) {
+	for _, typ := range []Type{STRING, NUMBER, BOOLEAN, DATE} {
+		if len(typ.Modes()) == 0 {
+			t.Errorf("expected match modes for type %s", typ)
+		}
+	}
+	if !slices.Contains(DATE.Modes(), filter.DATE_AFTER) || slices.Contains(DATE.Modes(), filter.CONTAINS) {
+		t.Errorf("expected the date modes, got %v", DATE.Modes())
+	}
+	if modes := Type("text").Modes(); modes != nil {
+		t.Errorf("expected no match modes for an unknown type, got %v", modes)
+	}
+}
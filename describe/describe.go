@@ -0,0 +1,311 @@
+package describe
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/prime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Words are the words and formats joining the parts of a description.
+type Words struct {
+	And        string // Joins the conditions of an AND group, e.g. "and"
+	Or         string // Joins the conditions of an OR group, e.g. "or"
+	Not        string // Negates a condition, e.g. "not"
+	Separator  string // Separates the columns and the parts of an event, e.g. "; "
+	SortedBy   string // Format of the sort order, receiving the sorted fields, e.g. "sorted by %s"
+	Ascending  string // Ascending sort order, e.g. "ascending"
+	Descending string // Descending sort order, e.g. "descending"
+	Rows       string // Format of the paging, receiving the first and last row, e.g. "rows %d to %d"
+	Global     string // Format of the global filter, receiving its value, e.g. "matching %s"
+	DateLayout string // Layout of the values of date columns, e.g. "2006-01-02"
+}
+
+// Describer renders lazy load events as human-readable text for support staff and audit logs, e.g.
+//
+//	Name starts with 'James' and ends with 'Butt'; Activity between 68 and 100; sorted by Date descending
+//
+// Templates are fmt formats receiving the label of the column followed by the formatted values,
+// so translations can reorder them with explicit argument indexes, e.g. "%[2]s ist der Wert von %[1]s".
+// Values of list match modes are joined into a single argument. When several conditions on the same column
+// are combined, the label is only written in the first one.
+type Describer struct {
+	Columns   column.Configs              // Configuration of the fields, providing their labels and types; may be nil
+	Templates map[filter.MatchMode]string // Formats of the match modes
+	ListModes []filter.MatchMode          // Match modes whose values are joined into a single argument
+	Words     Words                       // Words joining the parts of a description
+}
+
+// New creates a new Describer with English templates and words.
+// Parameters:
+//
+//	columns: The configuration of the fields, e.g. derived with column.FromStruct; may be nil.
+//
+// Returns:
+//
+//	A pointer to a newly created Describer instance.
+func New(columns column.Configs) *Describer {
+	return &Describer{
+		Columns:   columns,
+		Templates: DefaultTemplates(),
+		ListModes: []filter.MatchMode{filter.IN},
+		Words:     DefaultWords(),
+	}
+}
+
+// DefaultTemplates returns the English templates of the built-in match modes.
+func DefaultTemplates() map[filter.MatchMode]string {
+	return map[filter.MatchMode]string{
+		filter.EQUALS:              "%[1]s equals %[2]s",
+		filter.NOT_EQUALS:          "%[1]s does not equal %[2]s",
+		filter.CONTAINS:            "%[1]s contains %[2]s",
+		filter.NOT_CONTAINS:        "%[1]s does not contain %[2]s",
+		filter.STARTS_WITH:         "%[1]s starts with %[2]s",
+		filter.ENDS_WITH:           "%[1]s ends with %[2]s",
+		filter.LESS_THAN:           "%[1]s is less than %[2]s",
+		filter.LESS_THAN_EQUALS:    "%[1]s is at most %[2]s",
+		filter.GREATER_THAN:        "%[1]s is greater than %[2]s",
+		filter.GREATER_THAN_EQUALS: "%[1]s is at least %[2]s",
+		filter.DATE_BEFORE:         "%[1]s is before %[2]s",
+		filter.DATE_AFTER:          "%[1]s is after %[2]s",
+		filter.DATE_IS:             "%[1]s is on %[2]s",
+		filter.DATE_IS_NOT:         "%[1]s is not on %[2]s",
+		filter.IN:                  "%[1]s is one of %[2]s",
+		filter.BETWEEN:             "%[1]s between %[2]s and %[3]s",
+	}
+}
+
+// DefaultWords returns the English words of a description.
+func DefaultWords() Words {
+	return Words{
+		And:        "and",
+		Or:         "or",
+		Not:        "not",
+		Separator:  "; ",
+		SortedBy:   "sorted by %s",
+		Ascending:  "ascending",
+		Descending: "descending",
+		Rows:       "rows %d to %d",
+		Global:     "matching %s",
+		DateLayout: time.DateOnly,
+	}
+}
+
+// Event describes the filters, global filter, sort order and paging of a lazy load event.
+// Parameters:
+//
+//	event: The lazy load event to describe.
+//
+// Returns:
+//
+//	The description, empty if the event neither filters, sorts nor pages.
+func (d *Describer) Event(event prime.LazyLoadEvent) string {
+	var parts []string
+	if filters := d.Specs(event.Filters); filters != "" {
+		parts = append(parts, filters)
+	}
+	if global := event.Global(); global != "" {
+		parts = append(parts, fmt.Sprintf(d.Words.Global, quote(global)))
+	}
+	if sorts := d.Sorts(event.Sorts()); sorts != "" {
+		parts = append(parts, sorts)
+	}
+	if event.Rows > 0 {
+		parts = append(parts, fmt.Sprintf(d.Words.Rows, event.First+1, event.First+event.Rows))
+	}
+	return strings.Join(parts, d.Words.Separator)
+}
+
+// Specs describes the filters of a lazy load event, one column after the other.
+// Constraints with a nil value are not described, as they are not applied.
+// Parameters:
+//
+//	specs: The Specs to describe.
+//
+// Returns:
+//
+//	The description, empty if nothing is filtered.
+func (d *Describer) Specs(specs prime.Specs) string {
+	return d.Expr(specs.Expr())
+}
+
+// Expr describes a filter tree. The groups of the root AND group are separated by Words.Separator,
+// and nested groups are enclosed in parentheses.
+// Parameters:
+//
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	The description, empty if the tree has no condition with a value.
+func (d *Describer) Expr(node expr.Node) string {
+	if g, ok := node.(*expr.Group); ok && g.Operator == expr.AND {
+		var parts []string
+		for _, n := range g.Nodes {
+			if part := d.describe(n, false); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return strings.Join(parts, d.Words.Separator)
+	}
+	return d.describe(node, false)
+}
+
+// Sorts describes a sort order, e.g. "sorted by Date descending, Name ascending".
+// Parameters:
+//
+//	sorts: The sort order, e.g. from LazyLoadEvent.Sorts.
+//
+// Returns:
+//
+//	The description, empty if sorts is empty.
+func (d *Describer) Sorts(sorts []prime.SortMeta) string {
+	if len(sorts) == 0 {
+		return ""
+	}
+	fields := make([]string, len(sorts))
+	for i, s := range sorts {
+		direction := d.Words.Ascending
+		if s.Order < 0 {
+			direction = d.Words.Descending
+		}
+		fields[i] = d.label(s.Field) + " " + direction
+	}
+	return fmt.Sprintf(d.Words.SortedBy, strings.Join(fields, ", "))
+}
+
+func (d *Describer) describe(node expr.Node, nested bool) string {
+	switch n := node.(type) {
+	case *expr.Condition:
+		return d.condition(n, d.label(n.Column))
+	case *expr.Group:
+		return d.group(n, nested)
+	case *expr.Not:
+		inner := d.describe(n.Node, true)
+		if inner == "" {
+			return ""
+		}
+		return d.Words.Not + " " + inner
+	default:
+		return ""
+	}
+}
+
+func (d *Describer) group(g *expr.Group, nested bool) string {
+	word := d.Words.And
+	if g.Operator == expr.OR {
+		word = d.Words.Or
+	}
+
+	var (
+		parts []string
+		last  string // column of the previous condition, whose label is not repeated
+	)
+	for _, n := range g.Nodes {
+		var part string
+		if c, ok := n.(*expr.Condition); ok && c.Value != nil {
+			label := d.label(c.Column)
+			if c.Column == last {
+				label = ""
+			}
+			part, last = d.condition(c, label), c.Column
+		} else {
+			part, last = d.describe(n, true), ""
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	joined := strings.Join(parts, " "+word+" ")
+	if nested && len(parts) > 1 {
+		return "(" + joined + ")"
+	}
+	return joined
+}
+
+// condition describes a condition, leaving out the label when it is empty.
+func (d *Describer) condition(c *expr.Condition, label string) string {
+	if c.Value == nil {
+		return ""
+	}
+
+	values := c.Values()
+	formatted := make([]any, 0, len(values)+1)
+	formatted = append(formatted, label)
+	if slices.Contains(d.ListModes, c.MatchMode) {
+		list := make([]string, len(values))
+		for i, v := range values {
+			list[i] = d.value(c.Column, v)
+		}
+		formatted = append(formatted, strings.Join(list, ", "))
+	} else {
+		for _, v := range values {
+			formatted = append(formatted, d.value(c.Column, v))
+		}
+	}
+
+	template, ok := d.Templates[c.MatchMode]
+	if !ok {
+		template = "%[1]s " + string(c.MatchMode) + " %[2]s"
+	}
+	return strings.TrimSpace(fmt.Sprintf(template, formatted...))
+}
+
+// label returns the display name of a field, or the field itself if it has no label.
+func (d *Describer) label(field string) string {
+	if config, ok := d.Columns[field]; ok && config.Label != "" {
+		return config.Label
+	}
+	return field
+}
+
+// value formats a value: strings are quoted and escaped, and dates are formatted with Words.DateLayout.
+func (d *Describer) value(field string, v any) string {
+	switch value := v.(type) {
+	case time.Time:
+		return value.Format(d.Words.DateLayout)
+	case string:
+		if d.Columns[field].Type == column.DATE {
+			if t, err := time.Parse(time.RFC3339, value); err == nil {
+				return t.Format(d.Words.DateLayout)
+			}
+		}
+		return quote(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// quote wraps a string in single quotes, escaping quotes, backslashes and non-printable characters
+// as strconv.Quote does, so a value cannot forge the text around it.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch {
+		case r == '\'' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case strconv.IsPrint(r):
+			b.WriteRune(r)
+		default:
+			escaped := strconv.QuoteRune(r)
+			b.WriteString(escaped[1 : len(escaped)-1])
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
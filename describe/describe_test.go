@@ -0,0 +1,125 @@
+package describe
+
+import (
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/prime"
+	"testing"
+)
+
+var columns = column.Configs{
+	"name":     {Field: "name", Type: column.STRING, Label: "Name"},
+	"activity": {Field: "activity", Type: column.NUMBER, Label: "Activity"},
+	"date":     {Field: "date", Type: column.DATE, Label: "Date"},
+	"status":   {Field: "status", Type: column.STRING, Label: "Status"},
+}
+
+func TestEvent(t *testing.T) {
+	event := prime.LazyLoadEvent{
+		First:     20,
+		Rows:      10,
+		SortField: "date",
+		SortOrder: -1,
+		Filters: prime.Specs{
+			"name": {
+				{Value: "James", MatchMode: filter.STARTS_WITH, Operator: "and"},
+				{Value: "Butt", MatchMode: filter.ENDS_WITH, Operator: "and"},
+			},
+			"activity": {{Value: []any{68.0, 100.0}, MatchMode: filter.BETWEEN}},
+			"status":   {{Value: nil, MatchMode: filter.EQUALS}},
+		},
+	}
+
+	expected := "Activity between 68 and 100; Name starts with 'James' and ends with 'Butt'; sorted by Date descending; rows 21 to 30"
+	if description := New(columns).Event(event); description != expected {
+		t.Errorf("expected %q, got %q", expected, description)
+	}
+}
+
+func TestSpecs(t *testing.T) {
+	tests := []struct {
+		name     string
+		specs    prime.Specs
+		expected string
+	}{
+		{
+			name:     "nothing filtered",
+			specs:    prime.Specs{"name": {{Value: nil, MatchMode: filter.STARTS_WITH}}},
+			expected: "",
+		},
+		{
+			name: "or operator",
+			specs: prime.Specs{"name": {
+				{Value: "Ja", MatchMode: filter.STARTS_WITH, Operator: "or"},
+				{Value: "Bob", MatchMode: filter.EQUALS, Operator: "or"},
+			}},
+			expected: "Name starts with 'Ja' or equals 'Bob'",
+		},
+		{
+			name:     "list",
+			specs:    prime.Specs{"status": {{Value: []any{"open", "closed"}, MatchMode: filter.IN}}},
+			expected: "Status is one of 'open', 'closed'",
+		},
+		{
+			name:     "date",
+			specs:    prime.Specs{"date": {{Value: "2024-08-12T21:00:00.000Z", MatchMode: filter.DATE_AFTER}}},
+			expected: "Date is after 2024-08-12",
+		},
+		{
+			name:     "unconfigured field and match mode",
+			specs:    prime.Specs{"country.name": {{Value: "Spain", MatchMode: "like"}}},
+			expected: "country.name like 'Spain'",
+		},
+	}
+
+	d := New(columns)
+	for _, test := range tests {
+		if description := d.Specs(test.specs); description != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, description)
+		}
+	}
+}
+
+func TestExpr(t *testing.T) {
+	node := expr.Or(
+		expr.And(expr.Cond("name", filter.STARTS_WITH, "Ja"), expr.Cond("activity", filter.GREATER_THAN, 50)),
+		&expr.Not{Node: expr.Cond("status", filter.EQUALS, "closed")},
+	)
+
+	expected := "(Name starts with 'Ja' and Activity is greater than 50) or not Status equals 'closed'"
+	if description := New(columns).Expr(node); description != expected {
+		t.Errorf("expected %q, got %q", expected, description)
+	}
+}
+
+func TestQuoteEscapesValues(t *testing.T) {
+	node := expr.Cond("name", filter.EQUALS, "x'; Name equals 'admin\n\\\x00")
+
+	expected := `Name equals 'x\'; Name equals \'admin\n\\\x00'`
+	if description := New(columns).Expr(node); description != expected {
+		t.Errorf("expected %q, got %q", expected, description)
+	}
+}
+
+func TestLocalized(t *testing.T) {
+	d := New(column.Configs{"name": {Field: "name", Label: "Nom"}})
+	d.Templates[filter.STARTS_WITH] = "%[1]s commence par %[2]s"
+	d.Templates[filter.ENDS_WITH] = "%[1]s se termine par %[2]s"
+	d.Words.And = "et"
+	d.Words.SortedBy = "trié par %s"
+	d.Words.Ascending = "croissant"
+
+	event := prime.LazyLoadEvent{
+		SortField: "name",
+		Filters: prime.Specs{"name": {
+			{Value: "Ja", MatchMode: filter.STARTS_WITH},
+			{Value: "es", MatchMode: filter.ENDS_WITH},
+		}},
+	}
+
+	expected := "Nom commence par 'Ja' et se termine par 'es'; trié par Nom croissant"
+	if description := d.Event(event); description != expected {
+		t.Errorf("expected %q, got %q", expected, description)
+	}
+}
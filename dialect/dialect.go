@@ -0,0 +1,29 @@
+package dialect
+
+import "github.com/AdamShannag/goprime/placeholder"
+
+// Dialect describes the SQL dialect of a database: its placeholder style and the limits
+// it puts on the parameters of a single statement.
+type Dialect struct {
+	Name          string                  // Name of the dialect
+	Placeholder   placeholder.Placeholder // Placeholder used for bound values
+	MaxParameters int                     // Maximum number of bound values in a statement
+	MaxListValues int                     // Maximum number of values in an IN list; 0 if only MaxParameters applies
+}
+
+var (
+	// Postgres is the dialect of PostgreSQL, which binds at most 65535 values per statement.
+	Postgres = Dialect{Name: "postgres", Placeholder: placeholder.Numbered("$"), MaxParameters: 65535}
+
+	// MySQL is the dialect of MySQL and MariaDB, which bind at most 65535 values per prepared statement.
+	MySQL = Dialect{Name: "mysql", Placeholder: placeholder.UnNumbered("?"), MaxParameters: 65535}
+
+	// SQLite is the dialect of SQLite, which binds at most 32766 values per statement since version 3.32.
+	SQLite = Dialect{Name: "sqlite", Placeholder: placeholder.UnNumbered("?"), MaxParameters: 32766}
+
+	// SQLServer is the dialect of Microsoft SQL Server, which binds at most 2100 values per request.
+	SQLServer = Dialect{Name: "sqlserver", Placeholder: placeholder.Numbered("@p"), MaxParameters: 2100}
+
+	// Oracle is the dialect of Oracle Database, which accepts at most 1000 values in an IN list.
+	Oracle = Dialect{Name: "oracle", Placeholder: placeholder.Numbered(":"), MaxParameters: 65535, MaxListValues: 1000}
+)
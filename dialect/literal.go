@@ -0,0 +1,208 @@
+package dialect
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Literal formats a value as an SQL literal of the dialect, for logs only: strings are quoted,
+// times are formatted as the dialect parses them and slices are expanded, e.g. as ARRAY['a','b'] for Postgres
+// and ('a','b') for the other dialects. Values implementing driver.Valuer are formatted by their value.
+// Literals must never be used to build queries that are run; bind the values instead.
+// Parameters:
+//
+//	v: The value to format.
+//
+// Returns:
+//
+//	The SQL literal.
+func (d Dialect) Literal(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return d.quote(value)
+	case []byte:
+		return d.bytes(value)
+	case bool:
+		return d.bool(value)
+	case time.Time:
+		return d.time(value)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(value)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case driver.Valuer:
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return "NULL"
+		}
+		inner, err := value.Value()
+		if err != nil {
+			return d.quote(fmt.Sprint(value))
+		}
+		return d.Literal(inner)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "NULL"
+		}
+		return d.Literal(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		elements := make([]string, rv.Len())
+		for i := range elements {
+			elements[i] = d.Literal(rv.Index(i).Interface())
+		}
+		if d.Name == Postgres.Name {
+			return "ARRAY[" + strings.Join(elements, ",") + "]"
+		}
+		return "(" + strings.Join(elements, ",") + ")"
+	default:
+		return d.quote(fmt.Sprint(v))
+	}
+}
+
+// Interpolate replaces the placeholders of a query with the literals of their values, for logs only.
+// Placeholders inside quoted strings and identifiers are left untouched.
+// Parameters:
+//
+//	query: The query, using the placeholder of the dialect.
+//	vals: The values bound by the placeholders.
+//
+// Returns:
+//
+//	The query with its values inlined as literals; placeholders without a value are left untouched.
+func (d Dialect) Interpolate(query string, vals []any) string {
+	literals := make([]string, len(vals))
+	for i, v := range vals {
+		literals[i] = d.Literal(v)
+	}
+	return d.interpolate(query, literals)
+}
+
+// InterpolateLiterals replaces the placeholders of a query with literals that are already formatted, as Interpolate.
+// Parameters:
+//
+//	query: The query, using the placeholder of the dialect.
+//	literals: The literals replacing the placeholders, e.g. formatted with Literal.
+//
+// Returns:
+//
+//	The query with its literals inlined; placeholders without a literal are left untouched.
+func (d Dialect) InterpolateLiterals(query string, literals []string) string {
+	return d.interpolate(query, literals)
+}
+
+func (d Dialect) interpolate(query string, literals []string) string {
+	if d.Placeholder == nil {
+		return query
+	}
+
+	var (
+		b        strings.Builder
+		prefix   = d.Placeholder.Get(1)
+		next     int  // index of the next literal of unnumbered placeholders
+		quote    byte // quote character of the string or identifier being read, if any
+		numbered = d.Placeholder.Numbered()
+	)
+	if numbered {
+		prefix = strings.TrimSuffix(prefix, "1")
+	}
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case strings.HasPrefix(query[i:], prefix):
+			if !numbered {
+				if next < len(literals) {
+					b.WriteString(literals[next])
+					next++
+					i += len(prefix) - 1
+					continue
+				}
+				break
+			}
+
+			end := i + len(prefix)
+			for end < len(query) && query[end] >= '0' && query[end] <= '9' {
+				end++
+			}
+			if n, err := strconv.Atoi(query[i+len(prefix) : end]); err == nil && n >= 1 && n <= len(literals) {
+				b.WriteString(literals[n-1])
+				i = end - 1
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// quote quotes a string, doubling single quotes; MySQL also escapes backslashes.
+func (d Dialect) quote(s string) string {
+	if d.Name == MySQL.Name {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func (d Dialect) bytes(b []byte) string {
+	h := hex.EncodeToString(b)
+	switch d.Name {
+	case Postgres.Name:
+		return `'\x` + h + `'`
+	case SQLServer.Name:
+		return "0x" + h
+	case Oracle.Name:
+		return "HEXTORAW('" + h + "')"
+	default:
+		return "X'" + h + "'"
+	}
+}
+
+func (d Dialect) bool(b bool) string {
+	switch d.Name {
+	case SQLite.Name, SQLServer.Name, Oracle.Name:
+		if b {
+			return "1"
+		}
+		return "0"
+	default:
+		if b {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+}
+
+func (d Dialect) time(t time.Time) string {
+	switch d.Name {
+	case Postgres.Name:
+		return "'" + t.Format("2006-01-02 15:04:05.999999-07:00") + "'"
+	case MySQL.Name:
+		return "'" + t.UTC().Format("2006-01-02 15:04:05.999999") + "'"
+	case SQLite.Name:
+		return "'" + t.Format("2006-01-02 15:04:05.999999999-07:00") + "'"
+	case SQLServer.Name:
+		return "'" + t.Format("2006-01-02T15:04:05.9999999-07:00") + "'"
+	case Oracle.Name:
+		return "TIMESTAMP '" + t.Format("2006-01-02 15:04:05.999999 -07:00") + "'"
+	default:
+		return "'" + t.Format(time.RFC3339Nano) + "'"
+	}
+}
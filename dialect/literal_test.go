@@ -0,0 +1,77 @@
+package dialect
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestLiteral(t *testing.T) {
+	date := time.Date(2024, 8, 12, 21, 30, 0, 0, time.UTC)
+	name := "Ja"
+
+	tests := []struct {
+		dialect  Dialect
+		value    any
+		expected string
+	}{
+		{Postgres, "o'brien", "'o''brien'"},
+		{MySQL, `o'brien\`, `'o''brien\\'`},
+		{Postgres, nil, "NULL"},
+		{Postgres, 42, "42"},
+		{Postgres, 0.5, "0.5"},
+		{Postgres, &name, "'Ja'"},
+		{Postgres, (*string)(nil), "NULL"},
+		{Postgres, true, "TRUE"},
+		{SQLServer, true, "1"},
+		{SQLite, false, "0"},
+		{Postgres, []byte{0xca, 0xfe}, `'\xcafe'`},
+		{MySQL, []byte{0xca, 0xfe}, "X'cafe'"},
+		{SQLServer, []byte{0xca, 0xfe}, "0xcafe"},
+		{Oracle, []byte{0xca, 0xfe}, "HEXTORAW('cafe')"},
+		{Postgres, date, "'2024-08-12 21:30:00+00:00'"},
+		{MySQL, date, "'2024-08-12 21:30:00'"},
+		{SQLite, date, "'2024-08-12 21:30:00+00:00'"},
+		{SQLServer, date, "'2024-08-12T21:30:00+00:00'"},
+		{Oracle, date, "TIMESTAMP '2024-08-12 21:30:00 +00:00'"},
+		{Dialect{}, date, "'2024-08-12T21:30:00Z'"},
+		{Postgres, []any{"a", 1}, "ARRAY['a',1]"},
+		{MySQL, []string{"a", "b"}, "('a','b')"},
+		{Postgres, sql.NullString{String: "x", Valid: true}, "'x'"},
+		{Postgres, sql.NullInt64{}, "NULL"},
+	}
+
+	for _, test := range tests {
+		if got := test.dialect.Literal(test.value); got != test.expected {
+			t.Errorf("%s: expected literal %s for %v, got %s", test.dialect.Name, test.expected, test.value, got)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	vals := make([]any, 11)
+	for i := range vals {
+		vals[i] = i + 1
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		query    string
+		vals     []any
+		expected string
+	}{
+		{Postgres, "a = $1 AND b = $10 AND c = $11", vals, "a = 1 AND b = 10 AND c = 11"},
+		{Postgres, "a = $1 AND b = '$2' AND c = $2", []any{"x", "y"}, "a = 'x' AND b = '$2' AND c = 'y'"},
+		{Postgres, "a = $1 AND b = $3", []any{"x"}, "a = 'x' AND b = $3"},
+		{SQLServer, "a = @p1 AND b = @p2", []any{"x", 2}, "a = 'x' AND b = 2"},
+		{Oracle, "a = :1 AND b = :2", []any{"x", 2}, "a = 'x' AND b = 2"},
+		{MySQL, "a = ? AND `b?` = ? AND c = ?", []any{"x", "y"}, "a = 'x' AND `b?` = 'y' AND c = ?"},
+		{Dialect{}, "a = ?", []any{"x"}, "a = ?"},
+	}
+
+	for _, test := range tests {
+		if got := test.dialect.Interpolate(test.query, test.vals); got != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.dialect.Name, test.expected, got)
+		}
+	}
+}
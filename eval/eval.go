@@ -0,0 +1,227 @@
+package eval
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/prime"
+	"reflect"
+)
+
+// Evaluator evaluates filter specifications against Go values instead of a database,
+// e.g. cached lists, API responses or test fixtures.
+// Struct fields are matched by the name of their prime tag, their json tag or their Go name,
+// map[string]any values by their keys, and dotted columns such as "country.name" walk nested values.
+type Evaluator struct {
+	matchers map[filter.MatchMode]Matcher // Map of match modes to their corresponding matchers
+}
+
+// New creates a new Evaluator initialized with DefaultMatchers.
+//
+// Returns:
+//
+//	A pointer to a newly created Evaluator instance.
+func New() *Evaluator {
+	return &Evaluator{matchers: DefaultMatchers()}
+}
+
+// RegisterMatcher adds or replaces the matcher for a specific match mode.
+// Parameters:
+//
+//	matchMode: The match mode for which to register the matcher.
+//	matcher: The matcher to be registered for the specified match mode.
+func (e *Evaluator) RegisterMatcher(matchMode filter.MatchMode, matcher Matcher) {
+	e.matchers[matchMode] = matcher
+}
+
+// truth is the result of a condition under SQL's three-valued logic.
+type truth int8
+
+const (
+	isFalse truth = iota
+	isTrue
+	isUnknown
+)
+
+type compiled func(v reflect.Value) truth
+
+// Predicate builds a predicate from a filter tree.
+// The predicate follows the semantics of the SQL rendering: conditions with a nil value are skipped,
+// and conditions on a nil field are unknown, so neither the condition nor its negation match,
+// as comparisons with NULL behave in SQL.
+// Parameters:
+//
+//	e: The Evaluator providing the matchers.
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	The predicate, or an error if a match mode has no matcher, an operator is unknown
+//	or a column is not a field of T.
+func Predicate[T any](e *Evaluator, node expr.Node) (func(T) bool, error) {
+	c, err := e.compile(reflect.TypeFor[T](), node)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return func(T) bool { return true }, nil
+	}
+	return func(item T) bool {
+		return c(reflect.ValueOf(&item).Elem()) == isTrue
+	}, nil
+}
+
+// Filter returns the items matching a filter tree, in their original order.
+// Parameters:
+//
+//	e: The Evaluator providing the matchers.
+//	items: The items to filter.
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	A new slice holding the matching items.
+func Filter[T any](e *Evaluator, items []T, node expr.Node) ([]T, error) {
+	predicate, err := Predicate[T](e, node)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]T, 0, len(items))
+	for _, item := range items {
+		if predicate(item) {
+			matching = append(matching, item)
+		}
+	}
+	return matching, nil
+}
+
+// Page applies a lazy load event to a list: it filters the items, sorts them and returns the requested page.
+// Parameters:
+//
+//	e: The Evaluator providing the matchers.
+//	items: The items to page; the slice is not modified.
+//	event: The lazy load event of the table.
+//
+// Returns:
+//
+//	page: The items of the requested page; every matching item if event.Rows is 0.
+//	total: The number of matching items, the totalRecords of the table.
+//	err: An error if the filters or the sort order are invalid.
+func Page[T any](e *Evaluator, items []T, event prime.LazyLoadEvent) (page []T, total int, err error) {
+	matching, err := Filter(e, items, event.Filters.Expr())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err = Sort(matching, event.Sorts()); err != nil {
+		return nil, 0, err
+	}
+
+	total = len(matching)
+	first := min(max(event.First, 0), total)
+	last := total
+	if event.Rows > 0 {
+		last = min(first+event.Rows, total)
+	}
+	return matching[first:last], total, nil
+}
+
+func (e *Evaluator) compile(t reflect.Type, node expr.Node) (compiled, error) {
+	switch n := node.(type) {
+	case *expr.Condition:
+		return e.compileCondition(t, n)
+	case *expr.Group:
+		return e.compileGroup(t, n)
+	case *expr.Not:
+		inner, err := e.compile(t, n.Node)
+		if err != nil || inner == nil {
+			return nil, err
+		}
+		return func(v reflect.Value) truth {
+			switch inner(v) {
+			case isTrue:
+				return isFalse
+			case isFalse:
+				return isTrue
+			default:
+				return isUnknown
+			}
+		}, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown node type [%T]", node)
+	}
+}
+
+func (e *Evaluator) compileGroup(t reflect.Type, g *expr.Group) (compiled, error) {
+	if g.Operator != expr.AND && g.Operator != expr.OR {
+		return nil, fmt.Errorf("unknown operator [%s]", g.Operator)
+	}
+
+	var children []compiled
+	for _, n := range g.Nodes {
+		c, err := e.compile(t, n)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			children = append(children, c)
+		}
+	}
+
+	if len(children) == 0 {
+		return nil, nil
+	}
+
+	// decisive is the result that ends the evaluation of the group: false for AND, true for OR.
+	decisive, otherwise := isFalse, isTrue
+	if g.Operator == expr.OR {
+		decisive, otherwise = isTrue, isFalse
+	}
+
+	return func(v reflect.Value) truth {
+		result := otherwise
+		for _, child := range children {
+			switch child(v) {
+			case decisive:
+				return decisive
+			case isUnknown:
+				result = isUnknown
+			}
+		}
+		return result
+	}, nil
+}
+
+func (e *Evaluator) compileCondition(t reflect.Type, c *expr.Condition) (compiled, error) {
+	matcher, ok := e.matchers[c.MatchMode]
+	if !ok {
+		return nil, fmt.Errorf("match mode not registered [%s]", c.MatchMode)
+	}
+
+	if err := checkPath(t, c.Column); err != nil {
+		return nil, err
+	}
+
+	if c.Value == nil {
+		return nil, nil
+	}
+
+	if values, ok := c.Value.([]any); c.MatchMode == filter.BETWEEN && (!ok || len(values) != 2) {
+		return nil, fmt.Errorf("%s: between value must be a list of 2 values, got [%v]", c.Column, c.Value)
+	}
+
+	column, value := c.Column, c.Value
+	return func(v reflect.Value) truth {
+		field := lookup(v, column)
+		if field == nil {
+			return isUnknown
+		}
+		if matcher(field, value) {
+			return isTrue
+		}
+		return isFalse
+	}, nil
+}
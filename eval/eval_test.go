@@ -0,0 +1,202 @@
+package eval
+
+import (
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/prime"
+	"testing"
+	"time"
+)
+
+type country struct {
+	Name string `json:"name"`
+}
+
+type customer struct {
+	ID             int       `json:"id"`
+	Name           string    `prime:"name"`
+	Country        *country  `json:"country"`
+	Representative string    `json:"representative"`
+	Date           time.Time `json:"date"`
+	Activity       int       `json:"activity"`
+	Verified       *bool     `json:"verified"`
+}
+
+func verified(v bool) *bool { return &v }
+
+func customers() []customer {
+	return []customer{
+		{ID: 1, Name: "James Butt", Country: &country{"Algeria"}, Representative: "Ioni Bowcher", Date: time.Date(2024, 8, 13, 0, 0, 0, 0, time.UTC), Activity: 17, Verified: verified(true)},
+		{ID: 2, Name: "Josephine Darakjy", Country: &country{"Egypt"}, Representative: "Amy Elsner", Date: time.Date(2024, 8, 19, 0, 0, 0, 0, time.UTC), Activity: 68},
+		{ID: 3, Name: "Art Venere", Country: nil, Representative: "Asiya Javayant", Date: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), Activity: 100, Verified: verified(false)},
+		{ID: 4, Name: "Lenna Paprocki", Country: &country{"Slovenia"}, Representative: "Xuxue Feng", Date: time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC), Activity: 37},
+	}
+}
+
+func ids(items []customer) []int {
+	result := make([]int, len(items))
+	for i, c := range items {
+		result[i] = c.ID
+	}
+	return result
+}
+
+func assertIDs(t *testing.T, expected []int, items []customer) {
+	t.Helper()
+	actual := ids(items)
+	if len(actual) != len(expected) {
+		t.Fatalf("expected ids %v, got %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected ids %v, got %v", expected, actual)
+		}
+	}
+}
+
+func TestMatchModes(t *testing.T) {
+	tests := []struct {
+		name      string
+		column    string
+		matchMode filter.MatchMode
+		value     any
+		expected  []int
+	}{
+		{"equals", "activity", filter.EQUALS, float64(68), []int{2}},
+		{"notEquals", "activity", filter.NOT_EQUALS, float64(68), []int{1, 3, 4}},
+		{"contains", "name", filter.CONTAINS, "en", []int{3, 4}},
+		{"notContains", "name", filter.NOT_CONTAINS, "en", []int{1, 2}},
+		{"startsWith", "name", filter.STARTS_WITH, "J", []int{1, 2}},
+		{"startsWith is case-sensitive", "name", filter.STARTS_WITH, "j", []int{}},
+		{"endsWith", "name", filter.ENDS_WITH, "Butt", []int{1}},
+		{"lt", "activity", filter.LESS_THAN, float64(37), []int{1}},
+		{"lte", "activity", filter.LESS_THAN_EQUALS, float64(37), []int{1, 4}},
+		{"gt", "activity", filter.GREATER_THAN, float64(68), []int{3}},
+		{"gte", "activity", filter.GREATER_THAN_EQUALS, float64(68), []int{2, 3}},
+		{"dateBefore", "date", filter.DATE_BEFORE, "2024-08-13T00:00:00.000Z", []int{3}},
+		{"dateAfter", "date", filter.DATE_AFTER, "2024-08-13T00:00:00.000Z", []int{2, 4}},
+		{"dateIs", "date", filter.DATE_IS, "2024-08-13T00:00:00.000Z", []int{1}},
+		{"dateIsNot", "date", filter.DATE_IS_NOT, "2024-08-13T00:00:00.000Z", []int{2, 3, 4}},
+		{"in", "representative", filter.IN, []any{"Amy Elsner", "Xuxue Feng"}, []int{2, 4}},
+		{"between", "activity", filter.BETWEEN, []any{float64(37), float64(100)}, []int{2, 3, 4}},
+		{"nested", "country.name", filter.EQUALS, "Egypt", []int{2}},
+		{"nil field", "country.name", filter.NOT_EQUALS, "Egypt", []int{1, 4}},
+		{"bool", "verified", filter.EQUALS, true, []int{1}},
+		{"nil value", "name", filter.EQUALS, nil, []int{1, 2, 3, 4}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			items, err := Filter(New(), customers(), expr.Cond(test.column, test.matchMode, test.value))
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			assertIDs(t, test.expected, items)
+		})
+	}
+}
+
+func TestPredicateThreeValuedLogic(t *testing.T) {
+	tree := &expr.Not{Node: expr.Or(
+		expr.Cond("country.name", filter.EQUALS, "Egypt"),
+		expr.Cond("activity", filter.GREATER_THAN, 100),
+	)}
+
+	items, err := Filter(New(), customers(), tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	// Art Venere has no country, so NOT (NULL = 'Egypt' OR false) is unknown and does not match, as in SQL.
+	assertIDs(t, []int{1, 4}, items)
+}
+
+func TestPredicateMap(t *testing.T) {
+	rows := []map[string]any{
+		{"name": "James", "country": map[string]any{"name": "Algeria"}},
+		{"name": "Josephine", "country.name": "Egypt"},
+		{"name": "Art"},
+	}
+
+	predicate, err := Predicate[map[string]any](New(), prime.Specs{
+		"country.name": {{Value: []any{"Algeria", "Egypt"}, MatchMode: filter.IN}},
+	}.Expr())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := []bool{true, true, false}
+	for i, row := range rows {
+		if predicate(row) != expected[i] {
+			t.Errorf("expected %v for row %v, got %v", expected[i], row, !expected[i])
+		}
+	}
+}
+
+func TestPredicateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		tree expr.Node
+	}{
+		{"unknown field", expr.Cond("password", filter.EQUALS, "a")},
+		{"unknown nested field", expr.Cond("country.code", filter.EQUALS, "a")},
+		{"unregistered match mode", expr.Cond("name", "soundsLike", "a")},
+		{"unknown operator", &expr.Group{Operator: "xor", Nodes: []expr.Node{expr.Cond("name", filter.EQUALS, "a")}}},
+		{"invalid between value", expr.Cond("activity", filter.BETWEEN, []any{1})},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Predicate[customer](New(), test.tree); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestRegisterMatcher(t *testing.T) {
+	e := New()
+	e.RegisterMatcher("even", func(field, _ any) bool {
+		n, ok := field.(int)
+		return ok && n%2 == 0
+	})
+
+	items, err := Filter(e, customers(), expr.Cond("id", "even", true))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	assertIDs(t, []int{2, 4}, items)
+}
+
+func TestPage(t *testing.T) {
+	event := prime.LazyLoadEvent{
+		First:     1,
+		Rows:      2,
+		SortField: "activity",
+		SortOrder: -1,
+		Filters: prime.Specs{
+			"activity": {{Value: float64(20), MatchMode: filter.GREATER_THAN}},
+		},
+	}
+
+	page, total, err := Page(New(), customers(), event)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if total != 3 {
+		t.Errorf("expected 3 matching items, got %d", total)
+	}
+	assertIDs(t, []int{2, 4}, page)
+}
+
+func TestPageOutOfRange(t *testing.T) {
+	page, total, err := Page(New(), customers(), prime.LazyLoadEvent{First: 10, Rows: 10})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if total != 4 || len(page) != 0 {
+		t.Errorf("expected an empty page of 4 items, got %d of %d", len(page), total)
+	}
+}
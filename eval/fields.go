@@ -0,0 +1,127 @@
+package eval
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldIndexes caches the index of the fields of a struct type by their filter name.
+var fieldIndexes sync.Map // map[reflect.Type]map[string][]int
+
+// fieldName returns the name a struct field is filtered by: the name of its prime tag,
+// the name of its json tag, or the name of the field itself.
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"prime", "json"} {
+		if tag, ok := f.Tag.Lookup(key); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+	}
+	return f.Name
+}
+
+func structFields(t reflect.Type) map[string][]int {
+	if cached, ok := fieldIndexes.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+
+	fields := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		if name := fieldName(f); name != "" {
+			if _, exists := fields[name]; !exists || len(f.Index) == 1 {
+				fields[name] = f.Index
+			}
+		}
+	}
+
+	fieldIndexes.Store(t, fields)
+	return fields
+}
+
+// checkPath verifies that a dotted field path can be resolved on a type.
+// Paths through maps and interfaces are only resolved at evaluation time.
+func checkPath(t reflect.Type, path string) error {
+	for _, segment := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			index, ok := structFields(t)[segment]
+			if !ok {
+				return fmt.Errorf("unknown field [%s] on %s", path, t)
+			}
+			t = t.FieldByIndex(index).Type
+		case reflect.Map, reflect.Interface:
+			return nil
+		default:
+			return fmt.Errorf("unknown field [%s] on %s", path, t)
+		}
+	}
+	return nil
+}
+
+// lookup returns the value of a dotted field path on a struct or a map,
+// or nil if the path crosses a nil pointer, a nil interface or a missing map key.
+func lookup(v reflect.Value, path string) any {
+	if v.Kind() == reflect.Map {
+		if value := mapIndex(v, path); value.IsValid() {
+			return value.Interface()
+		}
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		v = indirect(v)
+		if !v.IsValid() {
+			return nil
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			index, ok := structFields(v.Type())[segment]
+			if !ok {
+				return nil
+			}
+			var err error
+			if v, err = v.FieldByIndexErr(index); err != nil {
+				return nil
+			}
+		case reflect.Map:
+			v = mapIndex(v, segment)
+		default:
+			return nil
+		}
+	}
+
+	v = indirect(v)
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func mapIndex(m reflect.Value, key string) reflect.Value {
+	if m.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}
+	}
+	return m.MapIndex(reflect.ValueOf(key).Convert(m.Type().Key()))
+}
@@ -0,0 +1,187 @@
+package eval
+
+import (
+	"github.com/AdamShannag/goprime/filter"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Matcher reports whether the value of a field matches the value of a condition.
+// The field value is never nil; conditions on a nil field are unknown, as comparisons
+// with NULL are in SQL.
+type Matcher func(field any, value any) bool
+
+// DefaultMatchers returns the matchers for every filter.MatchMode, matching the semantics
+// of the SQL filters in the filters package: text modes are case-sensitive like LIKE,
+// between is inclusive and the date modes compare RFC 3339 strings as timestamps.
+func DefaultMatchers() map[filter.MatchMode]Matcher {
+	return map[filter.MatchMode]Matcher{
+		filter.EQUALS:              func(f, v any) bool { return equal(f, v) },
+		filter.NOT_EQUALS:          func(f, v any) bool { return !equal(f, v) },
+		filter.CONTAINS:            text(strings.Contains),
+		filter.NOT_CONTAINS:        text(func(s, substr string) bool { return !strings.Contains(s, substr) }),
+		filter.STARTS_WITH:         text(strings.HasPrefix),
+		filter.ENDS_WITH:           text(strings.HasSuffix),
+		filter.LESS_THAN:           ordered(func(c int) bool { return c < 0 }),
+		filter.LESS_THAN_EQUALS:    ordered(func(c int) bool { return c <= 0 }),
+		filter.GREATER_THAN:        ordered(func(c int) bool { return c > 0 }),
+		filter.GREATER_THAN_EQUALS: ordered(func(c int) bool { return c >= 0 }),
+		filter.DATE_BEFORE:         dates(ordered(func(c int) bool { return c < 0 })),
+		filter.DATE_AFTER:          dates(ordered(func(c int) bool { return c > 0 })),
+		filter.DATE_IS:             dates(func(f, v any) bool { return equal(f, v) }),
+		filter.DATE_IS_NOT:         dates(func(f, v any) bool { return !equal(f, v) }),
+		filter.IN:                  in,
+		filter.BETWEEN:             between,
+	}
+}
+
+func text(match func(s, substr string) bool) Matcher {
+	return func(f, v any) bool {
+		fs, ok := normalize(f).(string)
+		if !ok {
+			return false
+		}
+		vs, ok := normalize(v).(string)
+		return ok && match(fs, vs)
+	}
+}
+
+func ordered(match func(int) bool) Matcher {
+	return func(f, v any) bool {
+		c, ok := Compare(f, v)
+		return ok && match(c)
+	}
+}
+
+func dates(m Matcher) Matcher {
+	return func(f, v any) bool {
+		return m(toTime(f), toTime(v))
+	}
+}
+
+func in(f, v any) bool {
+	values, ok := v.([]any)
+	if !ok {
+		return equal(f, v)
+	}
+	for _, value := range values {
+		if equal(f, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func between(f, v any) bool {
+	values, ok := v.([]any)
+	if !ok || len(values) != 2 {
+		return false
+	}
+	from, ok := Compare(f, values[0])
+	if !ok {
+		return false
+	}
+	to, ok := Compare(f, values[1])
+	return ok && from >= 0 && to <= 0
+}
+
+func equal(a, b any) bool {
+	c, ok := Compare(a, b)
+	return ok && c == 0
+}
+
+// Compare compares two values of the same kind: numbers of any type, strings, times or booleans.
+// Parameters:
+//
+//	a: The first value.
+//	b: The second value.
+//
+// Returns:
+//
+//	-1, 0 or +1 if a is less than, equal to or greater than b, and false if the values are not comparable.
+func Compare(a, b any) (int, bool) {
+	a, b = normalize(a), normalize(b)
+
+	switch av := a.(type) {
+	case float64:
+		if bv, ok := b.(float64); ok {
+			return compareOrdered(av, bv), true
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), true
+		}
+		if bv, ok := b.(time.Time); ok {
+			if at, ok := toTime(av).(time.Time); ok {
+				return at.Compare(bv), true
+			}
+		}
+	case time.Time:
+		if bv, ok := toTime(b).(time.Time); ok {
+			return av.Compare(bv), true
+		}
+	case bool:
+		if bv, ok := b.(bool); ok {
+			switch {
+			case av == bv:
+				return 0, true
+			case bv:
+				return -1, true
+			default:
+				return 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func compareOrdered(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// normalize dereferences pointers and converts every number to float64,
+// like encoding/json does for the values of a filter.
+func normalize(v any) any {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	}
+	return rv.Interface()
+}
+
+var dateLayouts = []string{time.RFC3339Nano, time.DateTime, time.DateOnly}
+
+// toTime parses string values as timestamps and leaves other values unchanged.
+func toTime(v any) any {
+	s, ok := normalize(v).(string)
+	if !ok {
+		return normalize(v)
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package eval
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/prime"
+	"reflect"
+	"slices"
+)
+
+// Sort sorts items in place by the given sort order, keeping equal items in their original order.
+// Nil values sort after every other value in ascending order and before them in descending order,
+// like NULL values do in PostgreSQL.
+// Parameters:
+//
+//	items: The items to sort.
+//	sorts: The fields to sort by, in order of precedence.
+//
+// Returns:
+//
+//	An error if a sort field is not a field of T or a sort order is not 1 or -1.
+func Sort[T any](items []T, sorts []prime.SortMeta) error {
+	t := reflect.TypeFor[T]()
+	for _, s := range sorts {
+		if err := checkPath(t, s.Field); err != nil {
+			return err
+		}
+		if s.Order != 1 && s.Order != -1 {
+			return fmt.Errorf("invalid sort order [%d] for field [%s]", s.Order, s.Field)
+		}
+	}
+
+	if len(sorts) == 0 {
+		return nil
+	}
+
+	slices.SortStableFunc(items, func(a, b T) int {
+		va, vb := reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem()
+		for _, s := range sorts {
+			if c := compareFields(lookup(va, s.Field), lookup(vb, s.Field)); c != 0 {
+				return c * s.Order
+			}
+		}
+		return 0
+	})
+	return nil
+}
+
+func compareFields(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	c, _ := Compare(a, b)
+	return c
+}
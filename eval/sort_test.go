@@ -0,0 +1,41 @@
+package eval
+
+import (
+	"github.com/AdamShannag/goprime/prime"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		name     string
+		sorts    []prime.SortMeta
+		expected []int
+	}{
+		{"none", nil, []int{1, 2, 3, 4}},
+		{"ascending", []prime.SortMeta{{Field: "activity", Order: 1}}, []int{1, 4, 2, 3}},
+		{"descending", []prime.SortMeta{{Field: "date", Order: -1}}, []int{2, 4, 1, 3}},
+		{"nil last ascending", []prime.SortMeta{{Field: "country.name", Order: 1}}, []int{1, 2, 4, 3}},
+		{"nil first descending", []prime.SortMeta{{Field: "verified", Order: -1}}, []int{2, 4, 1, 3}},
+		{"multiple", []prime.SortMeta{{Field: "verified", Order: 1}, {Field: "id", Order: -1}}, []int{3, 1, 4, 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			items := customers()
+			if err := Sort(items, test.sorts); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			assertIDs(t, test.expected, items)
+		})
+	}
+}
+
+func TestSortErrors(t *testing.T) {
+	if err := Sort(customers(), []prime.SortMeta{{Field: "password", Order: 1}}); err == nil {
+		t.Error("expected an error for an unknown field, got nil")
+	}
+
+	if err := Sort(customers(), []prime.SortMeta{{Field: "name", Order: 2}}); err == nil {
+		t.Error("expected an error for an invalid order, got nil")
+	}
+}
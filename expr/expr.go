@@ -0,0 +1,65 @@
+package expr
+
+import (
+	"github.com/AdamShannag/goprime/filter"
+)
+
+// Operator is the boolean operator used to combine the nodes of a Group.
+type Operator string
+
+const (
+	AND Operator = "and"
+	OR  Operator = "or"
+)
+
+// Node is an element of a filter tree. It is implemented by *Condition, *Group and *Not.
+type Node interface {
+	node()
+}
+
+// Condition is a leaf of a filter tree. It applies the filter registered for MatchMode
+// to Column using Value, exactly like a single filter.Spec does.
+type Condition struct {
+	Column    string           `json:"column"`
+	MatchMode filter.MatchMode `json:"matchMode"`
+	Value     any              `json:"value"`
+}
+
+// Group combines its nodes with a single boolean operator.
+type Group struct {
+	Operator Operator
+	Nodes    []Node
+}
+
+// Not negates the node it wraps.
+type Not struct {
+	Node Node
+}
+
+func (*Condition) node() {}
+func (*Group) node()     {}
+func (*Not) node()       {}
+
+// Cond creates a new Condition leaf.
+// Parameters:
+//
+//	column: The name of the column to filter.
+//	matchMode: The match mode of the registered filter to apply.
+//	value: The value of the condition.
+//
+// Returns:
+//
+//	A pointer to a new Condition.
+func Cond(column string, matchMode filter.MatchMode, value any) *Condition {
+	return &Condition{Column: column, MatchMode: matchMode, Value: value}
+}
+
+// And creates a Group combining the given nodes with AND.
+func And(nodes ...Node) *Group {
+	return &Group{Operator: AND, Nodes: nodes}
+}
+
+// Or creates a Group combining the given nodes with OR.
+func Or(nodes ...Node) *Group {
+	return &Group{Operator: OR, Nodes: nodes}
+}
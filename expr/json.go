@@ -0,0 +1,89 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Parse decodes a JSON filter tree into a Node.
+//
+// A tree is made of groups, negations and conditions:
+//
+//	{"and": [ ... ]}                                      a Group combined with AND
+//	{"or": [ ... ]}                                       a Group combined with OR
+//	{"not": { ... }}                                      a negation of a single node
+//	{"column": "name", "matchMode": "in", "value": [...]} a Condition
+//
+// Parameters:
+//
+//	data: The JSON document to decode.
+//
+// Returns:
+//
+//	The root Node of the tree, or an error if the document is not a valid filter tree.
+func Parse(data []byte) (Node, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	if _, ok := raw["column"]; ok {
+		c := &Condition{}
+		if err := json.Unmarshal(data, c); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+
+	if len(raw) != 1 {
+		return nil, fmt.Errorf("node must have exactly one of [and, or, not, column], got %d keys", len(raw))
+	}
+
+	for key, value := range raw {
+		switch key {
+		case string(AND), string(OR):
+			return parseGroup(Operator(key), value)
+		case "not":
+			n, err := Parse(value)
+			if err != nil {
+				return nil, err
+			}
+			return &Not{Node: n}, nil
+		default:
+			return nil, fmt.Errorf("unknown node [%s]", key)
+		}
+	}
+
+	return nil, nil
+}
+
+func parseGroup(operator Operator, data json.RawMessage) (*Group, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("%s: %w", operator, err)
+	}
+
+	g := &Group{Operator: operator, Nodes: make([]Node, 0, len(items))}
+	for _, item := range items {
+		n, err := Parse(item)
+		if err != nil {
+			return nil, err
+		}
+		g.Nodes = append(g.Nodes, n)
+	}
+	return g, nil
+}
+
+// MarshalJSON encodes the Group as {"<operator>": [nodes...]}.
+func (g *Group) MarshalJSON() ([]byte, error) {
+	nodes := g.Nodes
+	if nodes == nil {
+		nodes = []Node{}
+	}
+	return json.Marshal(map[Operator][]Node{g.Operator: nodes})
+}
+
+// MarshalJSON encodes the negation as {"not": node}.
+func (n *Not) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]Node{"not": n.Node})
+}
@@ -0,0 +1,94 @@
+package expr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	data := `{
+		"and": [
+			{"or": [
+				{"column": "status", "matchMode": "equals", "value": "open"},
+				{"column": "priority", "matchMode": "gt", "value": 3}
+			]},
+			{"not": {"column": "owner", "matchMode": "in", "value": ["amy", "anna"]}}
+		]
+	}`
+
+	node, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	root, ok := node.(*Group)
+	if !ok || root.Operator != AND || len(root.Nodes) != 2 {
+		t.Fatalf("expected an AND group with 2 nodes, got %#v", node)
+	}
+
+	or, ok := root.Nodes[0].(*Group)
+	if !ok || or.Operator != OR || len(or.Nodes) != 2 {
+		t.Fatalf("expected an OR group with 2 nodes, got %#v", root.Nodes[0])
+	}
+
+	status, ok := or.Nodes[0].(*Condition)
+	if !ok || status.Column != "status" || status.MatchMode != "equals" || status.Value != "open" {
+		t.Errorf("unexpected condition %#v", or.Nodes[0])
+	}
+
+	not, ok := root.Nodes[1].(*Not)
+	if !ok {
+		t.Fatalf("expected a Not node, got %#v", root.Nodes[1])
+	}
+
+	owner, ok := not.Node.(*Condition)
+	if !ok || owner.Column != "owner" || len(owner.Value.([]any)) != 2 {
+		t.Errorf("unexpected negated condition %#v", not.Node)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not an object", `[]`},
+		{"unknown key", `{"xor": []}`},
+		{"multiple keys", `{"and": [], "or": []}`},
+		{"group not a list", `{"and": {}}`},
+		{"invalid child", `{"or": [{"nand": []}]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Parse([]byte(test.data)); err == nil {
+				t.Errorf("expected an error for %s, got nil", test.data)
+			}
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	tree := And(
+		Or(Cond("status", "equals", "open"), Cond("priority", "gt", 3)),
+		&Not{Node: Cond("owner", "in", []any{"amy"})},
+	)
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := `{"and":[{"or":[{"column":"status","matchMode":"equals","value":"open"},{"column":"priority","matchMode":"gt","value":3}]},{"not":{"column":"owner","matchMode":"in","value":["amy"]}}]}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	node, err := Parse(data)
+	if err != nil {
+		t.Fatalf("expected the marshalled tree to parse, got %v", err)
+	}
+	if g, ok := node.(*Group); !ok || len(g.Nodes) != 2 {
+		t.Errorf("expected the parsed tree to have 2 nodes, got %#v", node)
+	}
+}
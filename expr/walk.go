@@ -0,0 +1,126 @@
+package expr
+
+import "fmt"
+
+// Visitor visits the nodes of a filter tree.
+// Visit is called for every node encountered by Walk. If the returned visitor w is not nil,
+// Walk visits each of the children of node with w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses a filter tree in depth-first order, the same way ast.Walk does for Go syntax trees.
+// Parameters:
+//
+//	v: The Visitor to call for every node.
+//	node: The root node of the tree.
+func Walk(v Visitor, node Node) {
+	if node == nil {
+		return
+	}
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Group:
+		for _, child := range n.Nodes {
+			Walk(v, child)
+		}
+	case *Not:
+		Walk(v, n.Node)
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses a filter tree in depth-first order, calling fn for every node.
+// If fn returns false, the children of the node are not visited.
+// Parameters:
+//
+//	node: The root node of the tree.
+//	fn: The function to call for every node; it is called with nil after the children of a node.
+func Inspect(node Node, fn func(Node) bool) {
+	Walk(inspector(fn), node)
+}
+
+// Conditions returns every Condition of the tree in depth-first order.
+func Conditions(node Node) []*Condition {
+	var conditions []*Condition
+	Inspect(node, func(n Node) bool {
+		if c, ok := n.(*Condition); ok {
+			conditions = append(conditions, c)
+		}
+		return true
+	})
+	return conditions
+}
+
+// RewriteFunc replaces a node of a filter tree. Returning nil removes the node from its parent.
+type RewriteFunc func(Node) (Node, error)
+
+// Rewrite rebuilds a filter tree bottom-up, calling fn for every node after its children
+// have been rewritten. Groups and negations are copied, so the original tree is left untouched.
+// Parameters:
+//
+//	node: The root node of the tree.
+//	fn: The function that returns the replacement of a node.
+//
+// Returns:
+//
+//	The rewritten tree, or the first error returned by fn.
+func Rewrite(node Node, fn RewriteFunc) (Node, error) {
+	switch n := node.(type) {
+	case nil:
+		return nil, nil
+	case *Condition:
+		c := *n
+		return fn(&c)
+	case *Group:
+		g := &Group{Operator: n.Operator, Nodes: make([]Node, 0, len(n.Nodes))}
+		for _, child := range n.Nodes {
+			rewritten, err := Rewrite(child, fn)
+			if err != nil {
+				return nil, err
+			}
+			if rewritten != nil {
+				g.Nodes = append(g.Nodes, rewritten)
+			}
+		}
+		return fn(g)
+	case *Not:
+		rewritten, err := Rewrite(n.Node, fn)
+		if err != nil {
+			return nil, err
+		}
+		if rewritten == nil {
+			return nil, nil
+		}
+		return fn(&Not{Node: rewritten})
+	default:
+		return nil, fmt.Errorf("unknown node type [%T]", node)
+	}
+}
+
+// Values returns the values of the condition as a list.
+// A list value ([]any) is returned as is, a nil value yields no values and any other value
+// is returned as a single element list.
+func (c *Condition) Values() []any {
+	switch v := c.Value.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	default:
+		return []any{v}
+	}
+}
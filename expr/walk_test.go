@@ -0,0 +1,138 @@
+package expr
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestTree() *Group {
+	return And(
+		Or(Cond("status", "equals", "open"), Cond("priority", "gt", 3)),
+		&Not{Node: Cond("owner", "in", []any{"amy", "anna"})},
+	)
+}
+
+type countingVisitor struct {
+	nodes int
+	nils  int
+}
+
+func (v *countingVisitor) Visit(node Node) Visitor {
+	if node == nil {
+		v.nils++
+	} else {
+		v.nodes++
+	}
+	return v
+}
+
+func TestWalk(t *testing.T) {
+	v := &countingVisitor{}
+	Walk(v, newTestTree())
+
+	if v.nodes != 6 {
+		t.Errorf("expected 6 nodes to be visited, got %d", v.nodes)
+	}
+
+	if v.nils != 6 {
+		t.Errorf("expected 6 nil visits, got %d", v.nils)
+	}
+}
+
+func TestInspectSkipsChildren(t *testing.T) {
+	var columns []string
+	Inspect(newTestTree(), func(n Node) bool {
+		if _, ok := n.(*Not); ok {
+			return false
+		}
+		if c, ok := n.(*Condition); ok {
+			columns = append(columns, c.Column)
+		}
+		return true
+	})
+
+	if len(columns) != 2 || columns[0] != "status" || columns[1] != "priority" {
+		t.Errorf("expected columns [status priority], got %v", columns)
+	}
+}
+
+func TestConditions(t *testing.T) {
+	conditions := Conditions(newTestTree())
+	if len(conditions) != 3 {
+		t.Fatalf("expected 3 conditions, got %d", len(conditions))
+	}
+
+	if conditions[2].Column != "owner" {
+		t.Errorf("expected last condition on owner, got %s", conditions[2].Column)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	tree := newTestTree()
+
+	rewritten, err := Rewrite(tree, func(n Node) (Node, error) {
+		c, ok := n.(*Condition)
+		if !ok {
+			return n, nil
+		}
+		if c.Column == "owner" {
+			return nil, nil
+		}
+		c.Column = "t." + c.Column
+		return c, nil
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	conditions := Conditions(rewritten)
+	if len(conditions) != 2 {
+		t.Fatalf("expected 2 conditions after rewrite, got %d", len(conditions))
+	}
+
+	if conditions[0].Column != "t.status" || conditions[1].Column != "t.priority" {
+		t.Errorf("expected rewritten columns, got %s and %s", conditions[0].Column, conditions[1].Column)
+	}
+
+	if len(rewritten.(*Group).Nodes) != 1 {
+		t.Errorf("expected the emptied negation to be removed, got %d nodes", len(rewritten.(*Group).Nodes))
+	}
+
+	if Conditions(tree)[0].Column != "status" {
+		t.Error("expected the original tree to be left untouched")
+	}
+}
+
+func TestRewriteError(t *testing.T) {
+	expected := errors.New("boom")
+	_, err := Rewrite(newTestTree(), func(n Node) (Node, error) {
+		if _, ok := n.(*Not); ok {
+			return nil, expected
+		}
+		return n, nil
+	})
+
+	if !errors.Is(err, expected) {
+		t.Errorf("expected error %v, got %v", expected, err)
+	}
+}
+
+func TestConditionValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected int
+	}{
+		{"nil", nil, 0},
+		{"scalar", "a", 1},
+		{"list", []any{1, 2, 3}, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if n := len(Cond("c", "in", test.value).Values()); n != test.expected {
+				t.Errorf("expected %d values, got %d", test.expected, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"context"
+	"github.com/AdamShannag/goprime/placeholder"
+	"time"
+)
+
+// ContextFilter is a Filter that uses the context of the request, e.g. the time zone of the caller
+// or the claims of its token. When a registered filter implements ContextFilter, the context variants
+// of its methods are called instead of Apply and EnrichValue.
+type ContextFilter interface {
+	Filter
+
+	// ApplyContext creates an SQL condition string for the WHERE clause, like Apply.
+	// Parameters:
+	//   ctx: The context of the request.
+	//   column: The name of the SQL column to filter.
+	//   totalArguments: The total number of arguments in the query.
+	//   currentIndex: The index of the current argument.
+	//   placeholder: The placeholder interface for generating the placeholder string (e.g., "?").
+	// Returns:
+	//   A string representing the SQL condition to append to the WHERE clause.
+	ApplyContext(ctx context.Context, column string, totalArguments int, currentIndex int, placeholder placeholder.Placeholder) string
+
+	// EnrichValueContext modifies the value pointed to by `*any`, like EnrichValue.
+	// Parameters:
+	//   ctx: The context of the request.
+	//   value: A pointer to the value to be modified. The value is adjusted in place.
+	// Returns:
+	//   An error if the value could not be modified; otherwise, nil.
+	EnrichValueContext(ctx context.Context, value *any) error
+}
+
+// Request holds request-scoped settings that filters can read from the context.
+type Request struct {
+	Location *time.Location // Time zone of the caller; nil means UTC
+	Locale   string         // Locale of the caller, e.g. "de-DE"
+}
+
+type requestKey struct{}
+
+// WithRequest returns a copy of ctx carrying the settings of a request.
+// Parameters:
+//
+//	ctx: The context of the request.
+//	request: The settings of the request.
+//
+// Returns:
+//
+//	The derived context.
+func WithRequest(ctx context.Context, request Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+// RequestFromContext returns the settings of the request carried by ctx, or zero settings.
+func RequestFromContext(ctx context.Context) Request {
+	request, _ := ctx.Value(requestKey{}).(Request)
+	return request
+}
+
+// Loc returns the time zone of the request, defaulting to UTC.
+func (r Request) Loc() *time.Location {
+	if r.Location == nil {
+		return time.UTC
+	}
+	return r.Location
+}
@@ -3,6 +3,8 @@ package filter
 import "github.com/AdamShannag/goprime/placeholder"
 
 // Filter provides methods for constructing and modifying SQL filter conditions.
+// Filters that should not modify values in place can implement the stateless Renderer contract instead;
+// AsRenderer adapts a Filter to it.
 type Filter interface {
 	// Apply creates an SQL condition string for the WHERE clause.
 	// Parameters:
@@ -0,0 +1,170 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/placeholder"
+	"slices"
+	"strings"
+)
+
+// Renderer is the stateless contract of a filter: it renders the SQL fragment of a condition
+// together with the values it binds, without modifying the values it receives.
+//
+// Bound values are marked with "?" in the fragment, in the order of the returned args; the engine
+// replaces them with the placeholders of the query. A literal question mark is written as "??".
+type Renderer interface {
+	// Render creates an SQL condition string for the WHERE clause and the values it binds.
+	// Parameters:
+	//   ctx: The context of the request.
+	//   column: The name of the SQL column to filter.
+	//   values: The values of the condition; a single value is a list of one element.
+	// Returns:
+	//   The SQL condition with a "?" marker per bound value, the bound values,
+	//   or an error if the values are not supported by the filter.
+	Render(ctx context.Context, column string, values []any) (sql string, args []any, err error)
+}
+
+// RenderFunc is a function implementing the Renderer interface.
+type RenderFunc func(ctx context.Context, column string, values []any) (string, []any, error)
+
+func (f RenderFunc) Render(ctx context.Context, column string, values []any) (string, []any, error) {
+	return f(ctx, column, values)
+}
+
+// ValueRenderer is implemented by Renderers that depend on the shape of the value of a condition:
+// a list, even of a single element, or a single value. The engine calls RenderValue instead of Render
+// for them, see RenderValue.
+type ValueRenderer interface {
+	// RenderValue creates an SQL condition string for the WHERE clause and the values it binds, as Render.
+	// Parameters:
+	//   ctx: The context of the request.
+	//   column: The name of the SQL column to filter.
+	//   value: The value of the condition as sent by the client; a list value is a []any.
+	// Returns:
+	//   The SQL condition with a "?" marker per bound value, the bound values,
+	//   or an error if the value is not supported by the filter.
+	RenderValue(ctx context.Context, column string, value any) (sql string, args []any, err error)
+}
+
+// RenderValue renders the value of a condition with a Renderer: a ValueRenderer receives the value as it is,
+// and other Renderers receive its values, a list of one element for a single value.
+// Parameters:
+//
+//	ctx: The context of the request.
+//	r: The Renderer of the match mode of the condition.
+//	column: The name of the SQL column to filter.
+//	value: The value of the condition; a list value is a []any.
+//
+// Returns:
+//
+//	The SQL condition with a "?" marker per bound value and the bound values, or the error of the Renderer.
+func RenderValue(ctx context.Context, r Renderer, column string, value any) (string, []any, error) {
+	if vr, ok := r.(ValueRenderer); ok {
+		return vr.RenderValue(ctx, column, value)
+	}
+	values, ok := value.([]any)
+	if !ok {
+		values = []any{value}
+	}
+	return r.Render(ctx, column, values)
+}
+
+// AsRenderer adapts a Filter to the Renderer interface.
+// The value passed to EnrichValue is the single value of the condition, or a copy of its list of values,
+// so the values received are never modified. The returned Renderer is a ValueRenderer, so a list of
+// one element is passed to EnrichValue as a list, as the client sent it; Render, which only receives
+// the values, passes a single value for a list of one element. When f is a ContextFilter, its context
+// variants are called.
+// Parameters:
+//
+//	f: The Filter to adapt.
+//
+// Returns:
+//
+//	A Renderer calling EnrichValue and Apply.
+func AsRenderer(f Filter) Renderer {
+	return filterRenderer{f}
+}
+
+type filterRenderer struct {
+	filter Filter
+}
+
+// marker is the placeholder passed to Apply, replaced by "?" once literal question marks are escaped.
+const marker = placeholder.UnNumbered("\x00")
+
+func (r filterRenderer) Render(ctx context.Context, column string, values []any) (string, []any, error) {
+	if len(values) == 1 {
+		return r.RenderValue(ctx, column, values[0])
+	}
+	return r.RenderValue(ctx, column, values)
+}
+
+func (r filterRenderer) RenderValue(ctx context.Context, column string, value any) (string, []any, error) {
+	if values, ok := value.([]any); ok {
+		value = slices.Clone(values)
+	}
+
+	cf, isContextFilter := r.filter.(ContextFilter)
+
+	var err error
+	if isContextFilter {
+		err = cf.EnrichValueContext(ctx, &value)
+	} else {
+		err = r.filter.EnrichValue(&value)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	args, ok := value.([]any)
+	if !ok {
+		args = []any{value}
+	}
+
+	var sql string
+	if isContextFilter {
+		sql = cf.ApplyContext(ctx, column, len(args), 1, marker)
+	} else {
+		sql = r.filter.Apply(column, len(args), 1, marker)
+	}
+
+	sql = strings.ReplaceAll(sql, "?", "??")
+	return strings.ReplaceAll(sql, string(marker), "?"), args, nil
+}
+
+// Bind replaces the "?" markers of a fragment rendered by a Renderer with placeholders,
+// numbered from the given index, and unescapes literal question marks.
+// Parameters:
+//
+//	sql: The fragment rendered by a Renderer.
+//	args: The values bound by the fragment.
+//	currentIndex: The index of the first placeholder.
+//	placeholder: The placeholder interface for generating the placeholder strings.
+//
+// Returns:
+//
+//	The fragment with placeholders, or an error if the number of markers does not match the number of values.
+func Bind(sql string, args []any, currentIndex int, placeholder placeholder.Placeholder) (string, error) {
+	var b strings.Builder
+	bound := 0
+	for i := 0; i < len(sql); i++ {
+		if sql[i] != '?' {
+			b.WriteByte(sql[i])
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == '?' {
+			b.WriteByte('?')
+			i++
+			continue
+		}
+		b.WriteString(placeholder.Get(currentIndex + bound))
+		bound++
+	}
+
+	if bound != len(args) {
+		return "", fmt.Errorf("fragment [%s] has %d placeholders for %d values", sql, bound, len(args))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,142 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/placeholder"
+	"reflect"
+	"testing"
+)
+
+type likeFilter struct{}
+
+func (likeFilter) Apply(column string, _, currentIndex int, placeholder placeholder.Placeholder) string {
+	return fmt.Sprintf("(%s LIKE %s)", column, placeholder.Get(currentIndex))
+}
+
+func (likeFilter) EnrichValue(value *any) error {
+	*value = fmt.Sprintf("%s%%", *value)
+	return nil
+}
+
+type jsonKeyFilter struct{}
+
+func (jsonKeyFilter) Apply(column string, total, currentIndex int, placeholder placeholder.Placeholder) string {
+	return fmt.Sprintf("(%s ? %s OR %s ? %s)", column, placeholder.Get(currentIndex), column, placeholder.Get(currentIndex+1))
+}
+
+func (jsonKeyFilter) EnrichValue(value *any) error {
+	values := (*value).([]any)
+	values[0] = "mutated"
+	return nil
+}
+
+func TestAsRenderer(t *testing.T) {
+	sql, args, err := AsRenderer(likeFilter{}).Render(context.Background(), "name", []any{"Ja"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if sql != "(name LIKE ?)" {
+		t.Errorf("expected sql (name LIKE ?), got %s", sql)
+	}
+	if !reflect.DeepEqual(args, []any{"Ja%"}) {
+		t.Errorf("expected args [Ja%%], got %v", args)
+	}
+}
+
+func TestAsRendererDoesNotModifyValues(t *testing.T) {
+	values := []any{"a", "b"}
+	sql, args, err := AsRenderer(jsonKeyFilter{}).Render(context.Background(), "tags", values)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if sql != "(tags ?? ? OR tags ?? ?)" {
+		t.Errorf("expected literal question marks to be escaped, got %s", sql)
+	}
+	if !reflect.DeepEqual(args, []any{"mutated", "b"}) {
+		t.Errorf("expected args [mutated b], got %v", args)
+	}
+	if !reflect.DeepEqual(values, []any{"a", "b"}) {
+		t.Errorf("expected the values to be left unchanged, got %v", values)
+	}
+}
+
+type anyFilter struct{}
+
+func (anyFilter) Apply(column string, _, currentIndex int, placeholder placeholder.Placeholder) string {
+	return fmt.Sprintf("(%s = ANY(%s))", column, placeholder.Get(currentIndex))
+}
+
+func (anyFilter) EnrichValue(value *any) error {
+	*value = []any{*value}
+	return nil
+}
+
+func TestRenderValueKeepsSingleElementLists(t *testing.T) {
+	var received any
+	f := AsRenderer(enrichFunc(func(value *any) error {
+		received = *value
+		return nil
+	}))
+
+	if _, _, err := RenderValue(context.Background(), f, "status", []any{"x"}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !reflect.DeepEqual(received, []any{"x"}) {
+		t.Errorf("expected EnrichValue to receive the list [x], got %#v", received)
+	}
+
+	if _, _, err := RenderValue(context.Background(), f, "status", "x"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if received != "x" {
+		t.Errorf("expected EnrichValue to receive the value x, got %#v", received)
+	}
+
+	sql, args, err := RenderValue(context.Background(), AsRenderer(anyFilter{}), "status", []any{"x"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if sql != "(status = ANY(?))" || !reflect.DeepEqual(args, []any{[]any{"x"}}) {
+		t.Errorf("expected the list [x] as a single argument, got %s %v", sql, args)
+	}
+}
+
+type enrichFunc func(value *any) error
+
+func (enrichFunc) Apply(column string, total, currentIndex int, placeholder placeholder.Placeholder) string {
+	return fmt.Sprintf("(%s IN (%s))", column, placeholder.Get(currentIndex))
+}
+
+func (f enrichFunc) EnrichValue(value *any) error {
+	return f(value)
+}
+
+func TestBind(t *testing.T) {
+	tests := []struct {
+		name        string
+		sql         string
+		args        []any
+		expectedSql string
+		expectErr   bool
+	}{
+		{"single", "(name = ?)", []any{1}, "(name = $3)", false},
+		{"multiple", "(age BETWEEN ? AND ?)", []any{1, 2}, "(age BETWEEN $3 AND $4)", false},
+		{"literal", "(tags ?? ?)", []any{"a"}, "(tags ? $3)", false},
+		{"too few markers", "(name = ?)", []any{1, 2}, "", true},
+		{"too many markers", "(name = ? OR name = ?)", []any{1}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sql, err := Bind(test.sql, test.args, 3, placeholder.Numbered("$"))
+			if (err != nil) != test.expectErr {
+				t.Fatalf("expected error %v, got %v", test.expectErr, err)
+			}
+			if sql != test.expectedSql {
+				t.Errorf("expected sql %s, got %s", test.expectedSql, sql)
+			}
+		})
+	}
+}
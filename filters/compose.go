@@ -0,0 +1,296 @@
+package filters
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/placeholder"
+	"slices"
+)
+
+// NotFilter negates the condition of another filter, e.g. Not(ValueFilter("=")) behaves as ValueFilter("<>")
+// for non-null values.
+type NotFilter struct {
+	Inner filter.Filter // Filter whose condition is negated
+}
+
+// Not creates a filter negating the condition of f.
+// Parameters:
+//
+//	f: The filter to negate.
+//
+// Returns:
+//
+//	A NotFilter wrapping f.
+func Not(f filter.Filter) NotFilter {
+	return NotFilter{Inner: f}
+}
+
+// Apply creates the condition of the inner filter, negated.
+// Example:
+//
+//	For Not(ValueFilter("=")), column = "age" and currentIndex = 1, the result would be: "(NOT (age = $1))"
+func (f NotFilter) Apply(column string, totalArguments, currentIndex int, placeholder placeholder.Placeholder) string {
+	return f.ApplyContext(context.Background(), column, totalArguments, currentIndex, placeholder)
+}
+
+// ApplyContext creates the condition of the inner filter with the context of the request, negated.
+func (f NotFilter) ApplyContext(ctx context.Context, column string, totalArguments, currentIndex int, placeholder placeholder.Placeholder) string {
+	inner := applyContext(ctx, f.Inner, column, totalArguments, currentIndex, placeholder)
+	if enclosed(inner) {
+		return fmt.Sprintf("(NOT %s)", inner)
+	}
+	return fmt.Sprintf("(NOT (%s))", inner)
+}
+
+// EnrichValue enriches the value as the inner filter does.
+func (f NotFilter) EnrichValue(value *any) error {
+	return f.EnrichValueContext(context.Background(), value)
+}
+
+// EnrichValueContext enriches the value as the inner filter does, with the context of the request.
+func (f NotFilter) EnrichValueContext(ctx context.Context, value *any) error {
+	return enrichValueContext(ctx, f.Inner, value)
+}
+
+// TransformFunc pre-processes the value of a condition: a single value, or a []any for multi-valued match modes.
+type TransformFunc func(value any) (any, error)
+
+// TransformFilter pre-processes the value of a condition before another filter enriches it,
+// e.g. to lower-case it or to parse it, and fails the condition when the value cannot be processed.
+type TransformFilter struct {
+	Inner filter.Filter // Filter applied to the transformed value
+	Func  TransformFunc // Function transforming the value
+}
+
+// Transform creates a filter transforming the value of a condition with fn before f enriches it.
+// Parameters:
+//
+//	f: The filter applied to the transformed value.
+//	fn: The function transforming the value; its error is returned by EnrichValue.
+//
+// Returns:
+//
+//	A TransformFilter wrapping f.
+func Transform(f filter.Filter, fn TransformFunc) TransformFilter {
+	return TransformFilter{Inner: f, Func: fn}
+}
+
+// Apply creates the condition of the inner filter.
+func (f TransformFilter) Apply(column string, totalArguments, currentIndex int, placeholder placeholder.Placeholder) string {
+	return f.ApplyContext(context.Background(), column, totalArguments, currentIndex, placeholder)
+}
+
+// ApplyContext creates the condition of the inner filter with the context of the request.
+func (f TransformFilter) ApplyContext(ctx context.Context, column string, totalArguments, currentIndex int, placeholder placeholder.Placeholder) string {
+	return applyContext(ctx, f.Inner, column, totalArguments, currentIndex, placeholder)
+}
+
+// EnrichValue transforms the value, then enriches it as the inner filter does.
+// Returns:
+//
+//	The error of the transform function, or of the inner filter.
+func (f TransformFilter) EnrichValue(value *any) error {
+	return f.EnrichValueContext(context.Background(), value)
+}
+
+// EnrichValueContext transforms the value, then enriches it as the inner filter does with the context of the request.
+func (f TransformFilter) EnrichValueContext(ctx context.Context, value *any) error {
+	transformed, err := f.Func(*value)
+	if err != nil {
+		return err
+	}
+	*value = transformed
+	return enrichValueContext(ctx, f.Inner, value)
+}
+
+// ExprFilter applies another filter to an SQL expression of the column instead of the column itself,
+// e.g. "lower(%s)" for case-insensitive matching or "date_trunc('day', %s)" to compare days.
+type ExprFilter struct {
+	Inner  filter.Filter // Filter applied to the expression
+	Format string        // Format of the expression, with a single %s verb for the column
+}
+
+// OnExpr creates a filter applying f to an SQL expression of the column.
+// The format is trusted SQL and must never be derived from client input.
+// Parameters:
+//
+//	f: The filter applied to the expression.
+//	format: The format of the expression, e.g. "lower(%s)".
+//
+// Returns:
+//
+//	An ExprFilter wrapping f.
+func OnExpr(f filter.Filter, format string) ExprFilter {
+	return ExprFilter{Inner: f, Format: format}
+}
+
+// Apply creates the condition of the inner filter on the expression of the column.
+// Example:
+//
+//	For OnExpr(ValueFilter("="), "lower(%s)"), column = "name" and currentIndex = 1,
+//	the result would be: "(lower(name) = $1)"
+func (f ExprFilter) Apply(column string, totalArguments, currentIndex int, placeholder placeholder.Placeholder) string {
+	return f.ApplyContext(context.Background(), column, totalArguments, currentIndex, placeholder)
+}
+
+// ApplyContext creates the condition of the inner filter on the expression of the column with the context of the request.
+func (f ExprFilter) ApplyContext(ctx context.Context, column string, totalArguments, currentIndex int, placeholder placeholder.Placeholder) string {
+	return applyContext(ctx, f.Inner, fmt.Sprintf(f.Format, column), totalArguments, currentIndex, placeholder)
+}
+
+// EnrichValue enriches the value as the inner filter does.
+func (f ExprFilter) EnrichValue(value *any) error {
+	return f.EnrichValueContext(context.Background(), value)
+}
+
+// EnrichValueContext enriches the value as the inner filter does, with the context of the request.
+func (f ExprFilter) EnrichValueContext(ctx context.Context, value *any) error {
+	return enrichValueContext(ctx, f.Inner, value)
+}
+
+// NotRenderer negates the condition of another filter implementing the filter.Renderer contract,
+// as NotFilter does for a filter.Filter.
+type NotRenderer struct {
+	Inner filter.Renderer // Filter whose condition is negated
+}
+
+// NewNotRenderer creates a filter negating the condition of r.
+// Parameters:
+//
+//	r: The filter to negate, e.g. filter.AsRenderer(ValueFilter("=")).
+//
+// Returns:
+//
+//	A NotRenderer wrapping r.
+func NewNotRenderer(r filter.Renderer) NotRenderer {
+	return NotRenderer{Inner: r}
+}
+
+// Render creates the condition of the inner filter, negated.
+func (f NotRenderer) Render(ctx context.Context, column string, values []any) (string, []any, error) {
+	return f.RenderValue(ctx, column, single(values))
+}
+
+// RenderValue creates the condition of the inner filter for the value of a condition, negated.
+func (f NotRenderer) RenderValue(ctx context.Context, column string, value any) (string, []any, error) {
+	inner, args, err := filter.RenderValue(ctx, f.Inner, column, value)
+	if err != nil {
+		return "", nil, err
+	}
+	if enclosed(inner) {
+		return fmt.Sprintf("(NOT %s)", inner), args, nil
+	}
+	return fmt.Sprintf("(NOT (%s))", inner), args, nil
+}
+
+// TransformRenderer pre-processes the value of a condition before another filter implementing
+// the filter.Renderer contract renders it, as TransformFilter does for a filter.Filter.
+type TransformRenderer struct {
+	Inner filter.Renderer // Filter rendering the transformed value
+	Func  TransformFunc   // Function transforming the value
+}
+
+// NewTransformRenderer creates a filter transforming the value of a condition with fn before r renders it.
+// Parameters:
+//
+//	r: The filter rendering the transformed value.
+//	fn: The function transforming the value; its error is returned by Render.
+//
+// Returns:
+//
+//	A TransformRenderer wrapping r.
+func NewTransformRenderer(r filter.Renderer, fn TransformFunc) TransformRenderer {
+	return TransformRenderer{Inner: r, Func: fn}
+}
+
+// Render transforms the values, then creates the condition of the inner filter.
+// A single value is transformed on its own, as it is by TransformFilter.
+func (f TransformRenderer) Render(ctx context.Context, column string, values []any) (string, []any, error) {
+	return f.RenderValue(ctx, column, single(values))
+}
+
+// RenderValue transforms the value of a condition, then creates the condition of the inner filter.
+// Returns:
+//
+//	The error of the transform function, or of the inner filter.
+func (f TransformRenderer) RenderValue(ctx context.Context, column string, value any) (string, []any, error) {
+	transformed, err := f.Func(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return filter.RenderValue(ctx, f.Inner, column, transformed)
+}
+
+// ExprRenderer applies another filter implementing the filter.Renderer contract to an SQL expression
+// of the column, as ExprFilter does for a filter.Filter.
+type ExprRenderer struct {
+	Inner  filter.Renderer // Filter applied to the expression
+	Format string          // Format of the expression, with a single %s verb for the column
+}
+
+// NewExprRenderer creates a filter applying r to an SQL expression of the column.
+// The format is trusted SQL and must never be derived from client input.
+// Parameters:
+//
+//	r: The filter applied to the expression.
+//	format: The format of the expression, e.g. "lower(%s)".
+//
+// Returns:
+//
+//	An ExprRenderer wrapping r.
+func NewExprRenderer(r filter.Renderer, format string) ExprRenderer {
+	return ExprRenderer{Inner: r, Format: format}
+}
+
+// Render creates the condition of the inner filter on the expression of the column.
+func (f ExprRenderer) Render(ctx context.Context, column string, values []any) (string, []any, error) {
+	return f.RenderValue(ctx, column, single(values))
+}
+
+// RenderValue creates the condition of the inner filter for the value of a condition on the expression of the column.
+func (f ExprRenderer) RenderValue(ctx context.Context, column string, value any) (string, []any, error) {
+	return filter.RenderValue(ctx, f.Inner, fmt.Sprintf(f.Format, column), value)
+}
+
+// single returns the single value of a list of one element, or a copy of the list, as filter.AsRenderer does.
+func single(values []any) any {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return slices.Clone(values)
+}
+
+func applyContext(ctx context.Context, f filter.Filter, column string, totalArguments, currentIndex int, placeholder placeholder.Placeholder) string {
+	if cf, ok := f.(filter.ContextFilter); ok {
+		return cf.ApplyContext(ctx, column, totalArguments, currentIndex, placeholder)
+	}
+	return f.Apply(column, totalArguments, currentIndex, placeholder)
+}
+
+func enrichValueContext(ctx context.Context, f filter.Filter, value *any) error {
+	if cf, ok := f.(filter.ContextFilter); ok {
+		return cf.EnrichValueContext(ctx, value)
+	}
+	return f.EnrichValue(value)
+}
+
+// enclosed reports whether a condition is wrapped in a single pair of parentheses.
+func enclosed(condition string) bool {
+	if len(condition) < 2 || condition[0] != '(' {
+		return false
+	}
+	depth := 0
+	for i := 0; i < len(condition); i++ {
+		switch condition[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i == len(condition)-1
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package filters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/placeholder"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNot_Apply(t *testing.T) {
+	mockPlaceholder := placeholder.Numbered("$")
+
+	tests := []struct {
+		name           string
+		filter         filter.Filter
+		expectedOutput string
+	}{
+		{"value", Not(ValueFilter("=")), "(NOT (age = $1))"},
+		{"in", Not(InFilter(0)), "(NOT (age IN ($1,$2)))"},
+		{"double", Not(Not(ValueFilter("="))), "(NOT (NOT (age = $1)))"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := test.filter.Apply("age", 2, 1, mockPlaceholder)
+			if output != test.expectedOutput {
+				t.Errorf("expected %s, got %s", test.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestEnclosed(t *testing.T) {
+	tests := map[string]bool{
+		"(a = $1)":            true,
+		"((a = $1))":          true,
+		"(a = $1) OR (b = 1)": false,
+		"a = $1":              false,
+		"(a IN ($1,$2))":      true,
+		"":                    false,
+	}
+
+	for condition, expected := range tests {
+		if enclosed(condition) != expected {
+			t.Errorf("expected enclosed(%q) to be %v", condition, expected)
+		}
+	}
+}
+
+func TestTransform_EnrichValue(t *testing.T) {
+	lower := func(value any) (any, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got [%v]", value)
+		}
+		return strings.ToLower(s), nil
+	}
+
+	f := Transform(NewPatternMatchFilter("LIKE", AROUND), lower)
+
+	var value any = "JaMeS"
+	if err := f.EnrichValue(&value); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if value != "%james%" {
+		t.Errorf("expected value %%james%%, got %v", value)
+	}
+
+	value = 42
+	if err := f.EnrichValue(&value); err == nil {
+		t.Error("expected the error of the transform function, got nil")
+	}
+}
+
+func TestOnExpr_Apply(t *testing.T) {
+	f := OnExpr(ValueFilter(">"), "date_trunc('day', %s)")
+
+	output := f.Apply("date", 1, 3, placeholder.Numbered("$"))
+	if expected := "(date_trunc('day', date) > $3)"; output != expected {
+		t.Errorf("expected %s, got %s", expected, output)
+	}
+}
+
+func TestComposedCaseInsensitiveContains(t *testing.T) {
+	upper := func(value any) (any, error) { return strings.ToUpper(value.(string)), nil }
+	f := filter.AsRenderer(Not(OnExpr(Transform(NewPatternMatchFilter("LIKE", AROUND), upper), "upper(%s)")))
+
+	sql, args, err := f.Render(context.Background(), "name", []any{"ja"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if expected := "(NOT (upper(name) LIKE ?))"; sql != expected {
+		t.Errorf("expected %s, got %s", expected, sql)
+	}
+	if len(args) != 1 || args[0] != "%JA%" {
+		t.Errorf("expected args [%%JA%%], got %v", args)
+	}
+}
+
+func TestComposedPropagatesContext(t *testing.T) {
+	tokyo := time.FixedZone("Tokyo", 9*60*60)
+	ctx := filter.WithRequest(context.Background(), filter.Request{Location: tokyo})
+
+	f := Not(OnExpr(LocalDateFilter(filter.DATE_IS), "%s::timestamptz"))
+
+	var value any = "2024-08-12T21:00:00.000Z"
+	if err := f.EnrichValueContext(ctx, &value); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := time.Date(2024, 8, 13, 0, 0, 0, 0, tokyo)
+	if values := value.([]any); !values[0].(time.Time).Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, values[0])
+	}
+
+	output := f.ApplyContext(ctx, "date", 2, 1, placeholder.Numbered("$"))
+	if expected := "(NOT (date::timestamptz >= $1 AND date::timestamptz < $2))"; output != expected {
+		t.Errorf("expected %s, got %s", expected, output)
+	}
+
+	if err := Transform(ValueFilter("="), func(any) (any, error) { return nil, errors.New("boom") }).EnrichValueContext(ctx, &value); err == nil {
+		t.Error("expected the error of the transform function, got nil")
+	}
+}
+
+func TestComposedRenderers(t *testing.T) {
+	overlaps := filter.RenderFunc(func(ctx context.Context, column string, values []any) (string, []any, error) {
+		if len(values) != 2 {
+			return "", nil, fmt.Errorf("overlaps requires 2 values, got %d", len(values))
+		}
+		return fmt.Sprintf("(%s && tstzrange(?, ?))", column), values, nil
+	})
+	trim := func(value any) (any, error) {
+		values, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected a list, got [%v]", value)
+		}
+		return []any{strings.TrimSpace(values[0].(string)), strings.TrimSpace(values[1].(string))}, nil
+	}
+	f := NewNotRenderer(NewExprRenderer(NewTransformRenderer(overlaps, trim), "%s::tstzrange"))
+
+	sql, args, err := f.Render(context.Background(), "period", []any{" a", "b "})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if expected := "(NOT (period::tstzrange && tstzrange(?, ?)))"; sql != expected {
+		t.Errorf("expected %s, got %s", expected, sql)
+	}
+	if len(args) != 2 || args[0] != "a" || args[1] != "b" {
+		t.Errorf("expected args [a b], got %v", args)
+	}
+
+	if _, _, err := f.Render(context.Background(), "period", []any{"a"}); err == nil {
+		t.Error("expected the error of the transform function, got nil")
+	}
+}
+
+func TestComposedRenderersKeepTheShapeOfValues(t *testing.T) {
+	var received any
+	f := NewNotRenderer(filter.AsRenderer(Transform(InFilter(0), func(value any) (any, error) {
+		received = value
+		return value, nil
+	})))
+
+	sql, args, err := filter.RenderValue(context.Background(), f, "status", []any{"open"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if expected := "(NOT (status IN (?)))"; sql != expected || len(args) != 1 {
+		t.Errorf("expected %s with 1 value, got %s %v", expected, sql, args)
+	}
+	if _, ok := received.([]any); !ok {
+		t.Errorf("expected the inner filter to receive a list, got %#v", received)
+	}
+}
@@ -1,8 +1,11 @@
 package filters
 
 import (
+	"context"
 	"fmt"
+	"github.com/AdamShannag/goprime/filter"
 	"github.com/AdamShannag/goprime/placeholder"
+	"time"
 )
 
 type DateAfterFilter uint8
@@ -36,3 +39,90 @@ func (DateIsNotFilter) Apply(column string, _, currentIndex int, placeholder pla
 }
 
 func (DateIsNotFilter) EnrichValue(*any) error { return nil }
+
+// LocalDateFilter compares a date column with the calendar day of the filter value in the time zone of the request,
+// set with filter.WithRequest or prime.WithLocation, e.g. "2024-08-12T21:00:00.000Z" is 2024-08-13 in Europe/Istanbul.
+// The value is replaced by the start of that day, or the start of the day and of the next day for
+// filter.DATE_IS and filter.DATE_IS_NOT, so timestamps are matched by the day the caller sees.
+// Without a time zone in the context, days are computed in UTC.
+type LocalDateFilter filter.MatchMode
+
+// Apply creates an SQL condition string comparing the column with the day of the value.
+// Parameters:
+//
+//	column: The name of the SQL column to filter.
+//	_: The total number of arguments (ignored).
+//	currentIndex: The index of the current placeholder in the SQL query.
+//	placeholder: The placeholder interface for generating the placeholder string.
+//
+// Returns:
+//
+//	A string representing the SQL condition, e.g. "(date >= $1 AND date < $2)" for filter.DATE_IS.
+func (f LocalDateFilter) Apply(column string, _, currentIndex int, placeholder placeholder.Placeholder) string {
+	switch filter.MatchMode(f) {
+	case filter.DATE_IS:
+		return fmt.Sprintf("(%s >= %s AND %s < %s)", column, placeholder.Get(currentIndex), column, placeholder.Get(currentIndex+1))
+	case filter.DATE_IS_NOT:
+		return fmt.Sprintf("(%s < %s OR %s >= %s)", column, placeholder.Get(currentIndex), column, placeholder.Get(currentIndex+1))
+	case filter.DATE_BEFORE:
+		return fmt.Sprintf("(%s < %s)", column, placeholder.Get(currentIndex))
+	default:
+		return fmt.Sprintf("(%s >= %s)", column, placeholder.Get(currentIndex))
+	}
+}
+
+// ApplyContext creates the same SQL condition string as Apply; the time zone only affects the value.
+func (f LocalDateFilter) ApplyContext(_ context.Context, column string, totalArguments, currentIndex int, placeholder placeholder.Placeholder) string {
+	return f.Apply(column, totalArguments, currentIndex, placeholder)
+}
+
+// EnrichValue replaces the value by the bounds of its day in UTC.
+// Parameters:
+//
+//	value: A pointer to the date, a time.Time or an RFC 3339 string.
+//
+// Returns:
+//
+//	An error if the value is not a date.
+func (f LocalDateFilter) EnrichValue(value *any) error {
+	return f.EnrichValueContext(context.Background(), value)
+}
+
+// EnrichValueContext replaces the value by the bounds of its day in the time zone of the request.
+// Parameters:
+//
+//	ctx: The context carrying the filter.Request of the caller.
+//	value: A pointer to the date, a time.Time or an RFC 3339 string.
+//
+// Returns:
+//
+//	An error if the value is not a date.
+func (f LocalDateFilter) EnrichValueContext(ctx context.Context, value *any) error {
+	var t time.Time
+	switch v := (*value).(type) {
+	case time.Time:
+		t = v
+	case string:
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return fmt.Errorf("invalid date [%s]: %w", v, err)
+		}
+		t = parsed
+	default:
+		return fmt.Errorf("invalid date [%v]", *value)
+	}
+
+	t = t.In(filter.RequestFromContext(ctx).Loc())
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	next := start.AddDate(0, 0, 1)
+
+	switch filter.MatchMode(f) {
+	case filter.DATE_IS, filter.DATE_IS_NOT:
+		*value = []any{start, next}
+	case filter.DATE_BEFORE:
+		*value = start
+	default:
+		*value = next
+	}
+	return nil
+}
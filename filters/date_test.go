@@ -0,0 +1,88 @@
+package filters
+
+import (
+	"context"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/placeholder"
+	"testing"
+	"time"
+)
+
+func TestLocalDateFilter_Apply(t *testing.T) {
+	mockPlaceholder := placeholder.Numbered("$")
+
+	tests := []struct {
+		matchMode      filter.MatchMode
+		expectedOutput string
+	}{
+		{filter.DATE_IS, "(date >= $3 AND date < $4)"},
+		{filter.DATE_IS_NOT, "(date < $3 OR date >= $4)"},
+		{filter.DATE_BEFORE, "(date < $3)"},
+		{filter.DATE_AFTER, "(date >= $3)"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.matchMode), func(t *testing.T) {
+			output := LocalDateFilter(test.matchMode).Apply("date", 0, 3, mockPlaceholder)
+			if output != test.expectedOutput {
+				t.Errorf("expected %s, got %s", test.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestLocalDateFilter_EnrichValueContext(t *testing.T) {
+	istanbul := time.FixedZone("Istanbul", 3*60*60)
+	ctx := filter.WithRequest(context.Background(), filter.Request{Location: istanbul})
+
+	dayStart := time.Date(2024, 8, 13, 0, 0, 0, 0, istanbul)
+
+	tests := []struct {
+		matchMode filter.MatchMode
+		expected  []time.Time
+	}{
+		{filter.DATE_IS, []time.Time{dayStart, dayStart.AddDate(0, 0, 1)}},
+		{filter.DATE_IS_NOT, []time.Time{dayStart, dayStart.AddDate(0, 0, 1)}},
+		{filter.DATE_BEFORE, []time.Time{dayStart}},
+		{filter.DATE_AFTER, []time.Time{dayStart.AddDate(0, 0, 1)}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.matchMode), func(t *testing.T) {
+			var value any = "2024-08-12T21:00:00.000Z"
+			if err := LocalDateFilter(test.matchMode).EnrichValueContext(ctx, &value); err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+
+			values, ok := value.([]any)
+			if !ok {
+				values = []any{value}
+			}
+			if len(values) != len(test.expected) {
+				t.Fatalf("expected %d values, got %v", len(test.expected), value)
+			}
+			for i, expected := range test.expected {
+				if !values[i].(time.Time).Equal(expected) {
+					t.Errorf("expected %v at index %d, got %v", expected, i, values[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLocalDateFilter_EnrichValue(t *testing.T) {
+	var value any = "2024-08-12T21:00:00.000Z"
+	if err := LocalDateFilter(filter.DATE_BEFORE).EnrichValue(&value); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC)
+	if !value.(time.Time).Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, value)
+	}
+
+	value = 42
+	if err := LocalDateFilter(filter.DATE_IS).EnrichValue(&value); err == nil {
+		t.Error("expected an error for a value that is not a date, got nil")
+	}
+}
@@ -0,0 +1,224 @@
+package prime
+
+import (
+	"context"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/spec"
+	"maps"
+	"slices"
+)
+
+// Builder collects the configuration of a Filter and compiles it into an immutable ReadOnlyFilter with Build.
+// A Builder is not safe for concurrent use; it is meant to be configured once, e.g. at startup.
+type Builder struct {
+	filter *Filter // Filter under construction
+}
+
+// NewBuilder creates a new Builder configured by the given options, as New.
+// Parameters:
+//
+//	opts: The options configuring the Filter, e.g. WithDialect(dialect.Postgres).
+//
+// Returns:
+//
+//	A pointer to a newly created Builder instance.
+func NewBuilder(opts ...Option) *Builder {
+	return &Builder{filter: New(opts...)}
+}
+
+// RegisterFilter adds a new filter for a specific match mode, as Filter.RegisterFilter.
+func (b *Builder) RegisterFilter(matchMode filter.MatchMode, filter filter.Filter) {
+	b.filter.RegisterFilter(matchMode, filter)
+}
+
+// RegisterRenderer adds a new filter implementing filter.Renderer for a specific match mode, as Filter.RegisterRenderer.
+func (b *Builder) RegisterRenderer(matchMode filter.MatchMode, renderer filter.Renderer) {
+	b.filter.RegisterRenderer(matchMode, renderer)
+}
+
+// RegisterColumnValidator adds a new column validator, as Filter.RegisterColumnValidator.
+func (b *Builder) RegisterColumnValidator(validator column.Validator) {
+	b.filter.RegisterColumnValidator(validator)
+}
+
+// RegisterSpecValidator adds a new spec validator, as Filter.RegisterSpecValidator.
+func (b *Builder) RegisterSpecValidator(validator spec.Validator) {
+	b.filter.RegisterSpecValidator(validator)
+}
+
+// RegisterColumns adds the configuration of filterable fields, as Filter.RegisterColumns.
+func (b *Builder) RegisterColumns(columns column.Configs) {
+	b.filter.RegisterColumns(columns)
+}
+
+// RegisterColumnModes restricts the match modes that can be used on a column, as Filter.RegisterColumnModes.
+func (b *Builder) RegisterColumnModes(column string, modes ...filter.MatchMode) {
+	b.filter.RegisterColumnModes(column, modes...)
+}
+
+// RegisterRewriter adds a rewriter applied to every filter tree, as Filter.RegisterRewriter.
+func (b *Builder) RegisterRewriter(rewriter expr.RewriteFunc) {
+	b.filter.RegisterRewriter(rewriter)
+}
+
+// RegisterPredicate adds a mandatory predicate ANDed into every query, as Filter.RegisterPredicate.
+func (b *Builder) RegisterPredicate(predicate Predicate) {
+	b.filter.RegisterPredicate(predicate)
+}
+
+// SetLimits sets the complexity limits, as Filter.SetLimits.
+func (b *Builder) SetLimits(limits Limits) {
+	b.filter.SetLimits(limits)
+}
+
+// ReadOnlyFilter is the read-only view of a Filter: it generates and validates conditions, but cannot be configured.
+// Builder.Build returns one, and the packages integrating a Filter with other libraries accept one,
+// so they work with a Filter as well as with a built one.
+type ReadOnlyFilter interface {
+	Sql(specs Specs) (vals []any, condition string, err error)
+	SqlContext(ctx context.Context, specs Specs, opts ...RequestOption) (vals []any, condition string, err error)
+	SqlExpr(node expr.Node) (vals []any, condition string, err error)
+	SqlExprContext(ctx context.Context, node expr.Node, opts ...RequestOption) (vals []any, condition string, err error)
+	Debug(specs Specs) (string, error)
+	DebugExprContext(ctx context.Context, node expr.Node, opts ...RequestOption) (string, error)
+	Interpolate(query string, vals []any) string
+	OrderBy(sorts []SortMeta) (string, error)
+	ValidateColumns(specs Specs) error
+	ValidateExpr(node expr.Node) error
+	ValidateModes(node expr.Node) error
+	ValidateLimits(node expr.Node) error
+	Rewrite(node expr.Node) (expr.Node, error)
+	RewriteContext(ctx context.Context, node expr.Node) (expr.Node, error)
+	Predicates(ctx context.Context) ([]expr.Node, error)
+	Scope(ctx context.Context, node expr.Node) (expr.Node, error)
+	HasFilter(matchMode filter.MatchMode) bool
+	Dialect() dialect.Dialect
+	Placeholder() placeholder.Placeholder
+	Columns() column.Configs
+}
+
+var _ ReadOnlyFilter = (*Filter)(nil)
+
+// Build compiles the configuration into an immutable ReadOnlyFilter.
+// The returned filter holds a deep copy of the configuration, so the Builder can keep being used
+// to build other filters. It cannot be converted back to a *Filter, and all of its methods are safe for
+// concurrent use by multiple goroutines, provided the registered filters, validators, rewriters and
+// predicates are as well.
+//
+// Returns:
+//
+//	The immutable filter.
+func (b *Builder) Build() ReadOnlyFilter {
+	f := *b.filter
+	f.filters = maps.Clone(f.filters)
+	f.columnValidators = slices.Clone(f.columnValidators)
+	f.specValidators = slices.Clone(f.specValidators)
+	f.rewriters = slices.Clone(f.rewriters)
+	f.predicates = slices.Clone(f.predicates)
+	f.columns = cloneColumns(f.columns)
+	f.columnModes = maps.Clone(f.columnModes)
+	for col, modes := range f.columnModes {
+		f.columnModes[col] = slices.Clone(modes)
+	}
+	return built{filter: &f}
+}
+
+// cloneColumns returns a deep copy of the configuration of the filterable fields.
+func cloneColumns(columns column.Configs) column.Configs {
+	columns = maps.Clone(columns)
+	for field, config := range columns {
+		config.Modes = slices.Clone(config.Modes)
+		columns[field] = config
+	}
+	return columns
+}
+
+// built is the ReadOnlyFilter returned by Builder.Build. It hides its Filter, so the configuration
+// cannot be changed through a type assertion.
+type built struct {
+	filter *Filter
+}
+
+func (b built) Sql(specs Specs) ([]any, string, error) {
+	return b.filter.Sql(specs)
+}
+
+func (b built) SqlContext(ctx context.Context, specs Specs, opts ...RequestOption) ([]any, string, error) {
+	return b.filter.SqlContext(ctx, specs, opts...)
+}
+
+func (b built) SqlExpr(node expr.Node) ([]any, string, error) {
+	return b.filter.SqlExpr(node)
+}
+
+func (b built) SqlExprContext(ctx context.Context, node expr.Node, opts ...RequestOption) ([]any, string, error) {
+	return b.filter.SqlExprContext(ctx, node, opts...)
+}
+
+func (b built) Debug(specs Specs) (string, error) {
+	return b.filter.Debug(specs)
+}
+
+func (b built) DebugExprContext(ctx context.Context, node expr.Node, opts ...RequestOption) (string, error) {
+	return b.filter.DebugExprContext(ctx, node, opts...)
+}
+
+func (b built) Interpolate(query string, vals []any) string {
+	return b.filter.Interpolate(query, vals)
+}
+
+func (b built) OrderBy(sorts []SortMeta) (string, error) {
+	return b.filter.OrderBy(sorts)
+}
+
+func (b built) ValidateColumns(specs Specs) error {
+	return b.filter.ValidateColumns(specs)
+}
+
+func (b built) ValidateExpr(node expr.Node) error {
+	return b.filter.ValidateExpr(node)
+}
+
+func (b built) ValidateModes(node expr.Node) error {
+	return b.filter.ValidateModes(node)
+}
+
+func (b built) ValidateLimits(node expr.Node) error {
+	return b.filter.ValidateLimits(node)
+}
+
+func (b built) Rewrite(node expr.Node) (expr.Node, error) {
+	return b.filter.Rewrite(node)
+}
+
+func (b built) RewriteContext(ctx context.Context, node expr.Node) (expr.Node, error) {
+	return b.filter.RewriteContext(ctx, node)
+}
+
+func (b built) Predicates(ctx context.Context) ([]expr.Node, error) {
+	return b.filter.Predicates(ctx)
+}
+
+func (b built) Scope(ctx context.Context, node expr.Node) (expr.Node, error) {
+	return b.filter.Scope(ctx, node)
+}
+
+func (b built) HasFilter(matchMode filter.MatchMode) bool {
+	return b.filter.HasFilter(matchMode)
+}
+
+func (b built) Dialect() dialect.Dialect {
+	return b.filter.Dialect()
+}
+
+func (b built) Placeholder() placeholder.Placeholder {
+	return b.filter.Placeholder()
+}
+
+func (b built) Columns() column.Configs {
+	return b.filter.Columns()
+}
@@ -0,0 +1,105 @@
+package prime
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/placeholder"
+	"sync"
+	"testing"
+)
+
+func newBuilder() *Builder {
+	b := NewBuilder(WithPlaceholder(placeholder.Numbered("$")))
+	b.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+	b.RegisterFilter(filter.IN, filters.InFilter(0))
+	b.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+	b.RegisterColumnValidator(column.AllowedValidator{"name", "status"})
+	b.RegisterColumnModes("status", filter.EQUALS, filter.IN)
+	b.RegisterPredicate(func(ctx context.Context) (expr.Node, error) {
+		return expr.Cond("tenant_id", filter.EQUALS, ctx.Value(tenantKey{})), nil
+	})
+	b.SetLimits(Limits{MaxConditions: 10})
+	return b
+}
+
+func TestBuild(t *testing.T) {
+	b := newBuilder()
+	pf := b.Build()
+
+	b.RegisterFilter(filter.GREATER_THAN, filters.ValueFilter(">"))
+	if pf.HasFilter(filter.GREATER_THAN) {
+		t.Error("expected the built Filter to be unaffected by later registrations on the Builder")
+	}
+	if !b.Build().HasFilter(filter.GREATER_THAN) {
+		t.Error("expected a new Build to include the later registrations")
+	}
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	vals, condition, err := pf.SqlContext(ctx, Specs{"status": {{Value: "open", MatchMode: filter.EQUALS}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if expected := "(tenant_id = $1) and (((status = $2)))"; condition != expected || len(vals) != 2 {
+		t.Errorf("expected condition %s, got %s", expected, condition)
+	}
+}
+
+func TestBuildIsReadOnly(t *testing.T) {
+	b := newBuilder()
+	modes := []filter.MatchMode{filter.STARTS_WITH}
+	b.RegisterColumns(column.Configs{"name": {Field: "name", Column: "c.name", Modes: modes}})
+	pf := b.Build()
+
+	if _, ok := pf.(*Filter); ok {
+		t.Fatal("expected the built filter not to be a *Filter")
+	}
+
+	modes[0] = filter.EQUALS
+	pf.Columns()["name"].Modes[0] = filter.EQUALS
+	if _, _, err := pf.Sql(Specs{"name": {{Value: "Ja", MatchMode: filter.EQUALS}}}); err == nil {
+		t.Error("expected the match modes of the built filter to be unaffected by changes to the configuration, got nil")
+	}
+}
+
+// TestBuiltFilterConcurrentSql is meant to be run with the race detector: go test -race ./prime
+func TestBuiltFilterConcurrentSql(t *testing.T) {
+	pf := newBuilder().Build()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			tenant := fmt.Sprintf("tenant-%d", i)
+			specs := Specs{
+				"name":   {{Value: "Ja", MatchMode: filter.STARTS_WITH}},
+				"status": {{Value: []any{"open", tenant}, MatchMode: filter.IN}},
+			}
+
+			ctx := context.WithValue(context.Background(), tenantKey{}, tenant)
+			vals, condition, err := pf.SqlContext(ctx, specs)
+			if err != nil {
+				t.Errorf("expected nil error, got %v", err)
+				return
+			}
+
+			expectedCondition := "(tenant_id = $1) and (((name LIKE $2)) and ((status IN ($3,$4))))"
+			if condition != expectedCondition {
+				t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+			}
+			if len(vals) != 4 || vals[0] != tenant || vals[3] != tenant {
+				t.Errorf("expected the values of %s, got %v", tenant, vals)
+			}
+
+			if err = pf.ValidateColumns(specs); err != nil {
+				t.Errorf("expected nil error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
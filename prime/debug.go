@@ -0,0 +1,81 @@
+package prime
+
+import (
+	"context"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/expr"
+)
+
+// Redacted is the literal replacing the values of sensitive fields in debug output.
+const Redacted = "'***'"
+
+// Debug renders the condition of the Specs with its values inlined as literals of the dialect, for logs only.
+// See DebugExprContext.
+// Parameters:
+//
+//	specs: The Specs of the event.
+//
+// Returns:
+//
+//	The condition with its values inlined, or an error as returned by Sql.
+func (f *Filter) Debug(specs Specs) (string, error) {
+	return f.DebugExprContext(context.Background(), specs.Expr())
+}
+
+// DebugExprContext renders a filter tree as SqlExprContext does, with its values inlined as literals
+// of the dialect set with WithDialect instead of placeholders, e.g.
+//
+//	name LIKE 'James%' AND ssn = '***' AND date >= '2024-08-12 00:00:00+00'
+//
+// Strings are quoted and escaped, times are formatted and slices are expanded, see dialect.Dialect.Literal.
+// The values of fields configured as sensitive with RegisterColumns are replaced with Redacted, including
+// when a rewriter maps them to other columns.
+// The output is meant for logs only: run the query with the values bound, as returned by SqlExprContext.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the registered predicates and context-aware filters.
+//	node: The root node of the filter tree.
+//	opts: Request settings added to the context, e.g. WithLocation.
+//
+// Returns:
+//
+//	The condition with its values inlined, or an error as returned by SqlExprContext.
+func (f *Filter) DebugExprContext(ctx context.Context, node expr.Node, opts ...RequestOption) (string, error) {
+	r, condition, err := f.render(ctx, node, opts)
+	if err != nil {
+		return "", err
+	}
+
+	d := f.literalDialect()
+	literals := make([]string, len(r.vals))
+	for i, v := range r.vals {
+		if r.redacted[i] {
+			literals[i] = Redacted
+			continue
+		}
+		literals[i] = d.Literal(v)
+	}
+	return d.InterpolateLiterals(condition, literals), nil
+}
+
+// Interpolate replaces the placeholders of a query generated with the Filter with the literals of their values,
+// for logs only, e.g. to log a query built around the condition returned by SqlContext.
+// Values are not redacted, since their fields are unknown; use DebugExprContext to redact sensitive fields.
+// Parameters:
+//
+//	query: The query, using the placeholder of the Filter.
+//	vals: The values bound by the placeholders.
+//
+// Returns:
+//
+//	The query with its values inlined as literals of the dialect set with WithDialect.
+func (f *Filter) Interpolate(query string, vals []any) string {
+	return f.literalDialect().Interpolate(query, vals)
+}
+
+// literalDialect returns the dialect of the Filter, using the placeholder of the Filter.
+func (f *Filter) literalDialect() dialect.Dialect {
+	d := f.dialect
+	d.Placeholder = f.placeholder
+	return d
+}
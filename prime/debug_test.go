@@ -0,0 +1,106 @@
+package prime
+
+import (
+	"context"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"testing"
+	"time"
+)
+
+func TestDebug(t *testing.T) {
+	pf := New(WithDialect(dialect.Postgres), WithFilters(map[filter.MatchMode]filter.Filter{
+		filter.EQUALS:      filters.ValueFilter("="),
+		filter.IN:          filters.InFilter(0),
+		filter.STARTS_WITH: filters.NewPatternMatchFilter("LIKE", filters.POST),
+	}))
+	pf.RegisterColumns(column.Configs{
+		"name": {Field: "name", Column: "c.name"},
+		"ssn":  {Field: "ssn", Column: "c.ssn", Sensitive: true},
+		"date": {Field: "date", Column: "c.date"},
+	})
+	pf.RegisterPredicate(tenantPredicate)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "o'brien")
+	tree := expr.And(
+		expr.Cond("name", filter.STARTS_WITH, "Ja"),
+		expr.Cond("ssn", filter.IN, []any{"123", "456"}),
+		expr.Cond("date", filter.EQUALS, time.Date(2024, 8, 12, 21, 0, 0, 0, time.UTC)),
+	)
+
+	debug, err := pf.DebugExprContext(ctx, tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := "(tenant_id = 'o''brien') and ((c.name LIKE 'Ja%') and (c.ssn IN ('***','***')) and (c.date = '2024-08-12 21:00:00+00:00'))"
+	if debug != expected {
+		t.Errorf("expected %s, got %s", expected, debug)
+	}
+
+	vals, _, err := pf.SqlExprContext(ctx, tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if vals[2] != "123" {
+		t.Errorf("expected sensitive values to stay bound, got %v", vals[2])
+	}
+}
+
+func TestDebugRedactsRewrittenFields(t *testing.T) {
+	pf := New(WithDialect(dialect.Postgres), WithFilters(map[filter.MatchMode]filter.Filter{
+		filter.EQUALS: filters.ValueFilter("="),
+	}))
+	pf.RegisterColumns(column.Configs{
+		"name": {Field: "name", Column: "c.name"},
+		"ssn":  {Field: "ssn", Column: "c.ssn", Sensitive: true},
+		"tax":  {Field: "tax", Column: "c.tax", Sensitive: true},
+	})
+	// ssn is kept in another table; tax is mapped to its configured column
+	pf.RegisterRewriter(func(n expr.Node) (expr.Node, error) {
+		if c, ok := n.(*expr.Condition); ok {
+			switch c.Column {
+			case "ssn":
+				c.Column = "identities.ssn"
+			case "tax":
+				c.Column = "c.tax"
+			}
+		}
+		return n, nil
+	})
+
+	tree := expr.And(
+		expr.Cond("name", filter.EQUALS, "James"),
+		expr.Cond("ssn", filter.EQUALS, "123"),
+		expr.Cond("tax", filter.EQUALS, "456"),
+	)
+	debug, err := pf.DebugExprContext(context.Background(), tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := "(c.name = 'James') and (identities.ssn = '***') and (c.tax = '***')"
+	if debug != expected {
+		t.Errorf("expected %s, got %s", expected, debug)
+	}
+}
+
+func TestDebugErrors(t *testing.T) {
+	pf := newTreeFilter()
+	if _, err := pf.Debug(Specs{"name": {{Value: "Ja", MatchMode: filter.CONTAINS}}}); err == nil {
+		t.Error("expected an error for an unregistered match mode, got nil")
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	pf := New(WithDialect(dialect.MySQL))
+	query := "SELECT * FROM customers WHERE name = ? AND note <> '?' AND active = ?"
+
+	expected := `SELECT * FROM customers WHERE name = 'it''s C:\\dir' AND note <> '?' AND active = TRUE`
+	if got := pf.Interpolate(query, []any{`it's C:\dir`, true}); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
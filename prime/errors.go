@@ -0,0 +1,49 @@
+package prime
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/filter"
+)
+
+// MatchModeError is returned when a match mode is used on a column that does not allow it.
+type MatchModeError struct {
+	Column    string             // Column the match mode was used on
+	MatchMode filter.MatchMode   // Match mode that is not allowed
+	Allowed   []filter.MatchMode // Match modes allowed on the column
+}
+
+func (e *MatchModeError) Error() string {
+	return fmt.Sprintf("match mode [%s] is not allowed on column [%s]", e.MatchMode, e.Column)
+}
+
+// ValidationError is returned when a column or spec validator rejects a condition.
+type ValidationError struct {
+	Validator string           // Name of the validator that rejected the condition
+	Column    string           // Column of the condition
+	MatchMode filter.MatchMode // Match mode of the condition
+	Err       error            // Error returned by the validator
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Validator, e.Err.Error())
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValueError is returned when the filter of a match mode rejects the value of a condition,
+// e.g. a date that cannot be parsed or a BETWEEN with a single value.
+type ValueError struct {
+	Column    string           // Column of the condition
+	MatchMode filter.MatchMode // Match mode of the condition
+	Err       error            // Error returned by the filter
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("invalid value for match mode [%s] on column [%s]: %s", e.MatchMode, e.Column, e.Err.Error())
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
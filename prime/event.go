@@ -0,0 +1,44 @@
+package prime
+
+// SortMeta describes the sort order of a single field.
+// An Order of 1 sorts ascending and -1 sorts descending, as in PrimeNG.
+type SortMeta struct {
+	Field string `json:"field"`
+	Order int    `json:"order"`
+}
+
+// LazyLoadEvent is the lazy load event sent by a PrimeNG table.
+// It holds the paging, sorting and filtering state of the table.
+type LazyLoadEvent struct {
+	First         int        `json:"first"`                   // Index of the first row to load
+	Rows          int        `json:"rows"`                    // Number of rows to load
+	SortField     string     `json:"sortField,omitempty"`     // Field to sort by in single sort mode
+	SortOrder     int        `json:"sortOrder,omitempty"`     // Sort order in single sort mode
+	MultiSortMeta []SortMeta `json:"multiSortMeta,omitempty"` // Fields to sort by in multiple sort mode
+	Filters       Specs      `json:"filters,omitempty"`       // Column filters
+	GlobalFilter  any        `json:"globalFilter,omitempty"`  // Value of the global filter
+}
+
+// Sorts returns the sort order of the event, taken from MultiSortMeta in multiple sort mode
+// and from SortField and SortOrder otherwise.
+func (e LazyLoadEvent) Sorts() []SortMeta {
+	if len(e.MultiSortMeta) > 0 {
+		return e.MultiSortMeta
+	}
+	if e.SortField == "" {
+		return nil
+	}
+	order := e.SortOrder
+	if order == 0 {
+		order = 1
+	}
+	return []SortMeta{{Field: e.SortField, Order: order}}
+}
+
+// Global returns the value of the global filter as a string, or an empty string if there is none.
+func (e LazyLoadEvent) Global() string {
+	if s, ok := e.GlobalFilter.(string); ok {
+		return s
+	}
+	return ""
+}
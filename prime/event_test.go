@@ -0,0 +1,61 @@
+package prime
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLazyLoadEventUnmarshal(t *testing.T) {
+	data := `{
+		"first": 20,
+		"rows": 10,
+		"sortField": "name",
+		"sortOrder": -1,
+		"filters": {"name": [{"value": "James", "matchMode": "startsWith", "operator": "and"}]},
+		"globalFilter": "ja"
+	}`
+
+	event := LazyLoadEvent{}
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if event.First != 20 || event.Rows != 10 {
+		t.Errorf("expected first 20 and rows 10, got %d and %d", event.First, event.Rows)
+	}
+
+	if len(event.Filters["name"]) != 1 {
+		t.Errorf("expected 1 filter on name, got %d", len(event.Filters["name"]))
+	}
+
+	if event.Global() != "ja" {
+		t.Errorf("expected global filter ja, got %s", event.Global())
+	}
+}
+
+func TestLazyLoadEventSorts(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    LazyLoadEvent
+		expected []SortMeta
+	}{
+		{"none", LazyLoadEvent{}, nil},
+		{"single", LazyLoadEvent{SortField: "name", SortOrder: -1}, []SortMeta{{"name", -1}}},
+		{"single without order", LazyLoadEvent{SortField: "name"}, []SortMeta{{"name", 1}}},
+		{"multiple", LazyLoadEvent{SortField: "name", MultiSortMeta: []SortMeta{{"date", -1}, {"name", 1}}}, []SortMeta{{"date", -1}, {"name", 1}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sorts := test.event.Sorts()
+			if len(sorts) != len(test.expected) {
+				t.Fatalf("expected %d sorts, got %d", len(test.expected), len(sorts))
+			}
+			for i, s := range test.expected {
+				if sorts[i] != s {
+					t.Errorf("expected sort %v at index %d, got %v", s, i, sorts[i])
+				}
+			}
+		})
+	}
+}
@@ -1,43 +1,62 @@
 package prime
 
 import (
-	"fmt"
+	"context"
 	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/expr"
 	"github.com/AdamShannag/goprime/filter"
 	"github.com/AdamShannag/goprime/placeholder"
-	"iter"
-	"strings"
+	"github.com/AdamShannag/goprime/spec"
+	"maps"
+	"slices"
 )
 
 // Filter manages a collection of SQL filters and their associated placeholders.
 // It stores filters by their match modes and provides methods for creating new filters
 // and registering additional filters. The placeholder used in SQL conditions is
 // represented by the Placeholder interface, allowing for different placeholder styles.
+//
+// Generating and validating conditions does not modify a Filter, so it is safe for concurrent use
+// once it is configured. Registering concurrently with other calls is not; use a Builder to configure
+// a Filter and share the immutable ReadOnlyFilter returned by Builder.Build between goroutines.
 type Filter struct {
-	filters          map[filter.MatchMode]filter.Filter // Map of match modes to their corresponding filters
-	placeholder      placeholder.Placeholder            // Placeholder interface used in SQL conditions
-	columnValidators column.Validators                  // Validators for column values
+	filters          map[filter.MatchMode]filter.Renderer // Map of match modes to their corresponding filters
+	placeholder      placeholder.Placeholder              // Placeholder interface used in SQL conditions
+	columnValidators column.Validators                    // Validators for column values
+	rewriters        []expr.RewriteFunc                   // Rewriters applied to every filter tree before rendering
+	columns          column.Configs                       // Configuration of the filterable fields, keyed by field name
+	columnModes      map[string][]filter.MatchMode        // Match modes allowed per column
+	specValidators   spec.Validators                      // Validators for the constraints of a column
+	limits           Limits                               // Complexity limits of the filters
+	predicates       []Predicate                          // Mandatory predicates ANDed into every query
+	dialect          dialect.Dialect                      // Dialect of the database, if set with WithDialect
 }
 
-// New creates a new Filter instance with the specified placeholder.
-// It initializes the filters map as an empty map and sets up column validators as an empty slice.
+// New creates a new Filter instance configured by the given options.
+// Without WithPlaceholder or WithDialect, the "?" placeholder is used.
 // Parameters:
 //
-//	placeholder: An implementation of the Placeholder interface for SQL conditions.
+//	opts: The options configuring the Filter, e.g. WithDialect(dialect.Postgres) or WithFilters(filters).
 //
 // Returns:
 //
 //	A pointer to a newly created Filter instance.
-func New(placeholder placeholder.Placeholder) *Filter {
-	return &Filter{
-		placeholder:      placeholder,
-		filters:          make(map[filter.MatchMode]filter.Filter),
+func New(opts ...Option) *Filter {
+	f := &Filter{
+		placeholder:      placeholder.UnNumbered("?"),
+		filters:          make(map[filter.MatchMode]filter.Renderer),
 		columnValidators: make(column.Validators, 0),
 	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 // NewWithFilters creates a new Filter instance with the specified placeholder
 // and initializes it with a map of filters.
+// It is equivalent to New(WithPlaceholder(placeholder), WithFilters(filters)).
 // Parameters:
 //
 //	placeholder: An implementation of the Placeholder interface for SQL conditions.
@@ -47,15 +66,12 @@ func New(placeholder placeholder.Placeholder) *Filter {
 //
 //	A pointer to a newly created Filter instance with the specified filters.
 func NewWithFilters(placeholder placeholder.Placeholder, filters map[filter.MatchMode]filter.Filter) *Filter {
-	return &Filter{
-		placeholder:      placeholder,
-		filters:          filters,
-		columnValidators: make(column.Validators, 0),
-	}
+	return New(WithPlaceholder(placeholder), WithFilters(filters))
 }
 
 // NewWithFiltersAndValidators creates a new Filter instance with the specified placeholder,
 // filters, and column validators.
+// It is equivalent to New(WithPlaceholder(placeholder), WithFilters(filters), WithValidators(validators...)).
 // Parameters:
 //
 //	placeholder: An implementation of the Placeholder interface for SQL conditions.
@@ -66,20 +82,38 @@ func NewWithFilters(placeholder placeholder.Placeholder, filters map[filter.Matc
 //
 //	A pointer to a newly created Filter instance with the specified filters and validators.
 func NewWithFiltersAndValidators(placeholder placeholder.Placeholder, filters map[filter.MatchMode]filter.Filter, validators column.Validators) *Filter {
-	return &Filter{
-		placeholder:      placeholder,
-		filters:          filters,
-		columnValidators: validators,
-	}
+	return New(WithPlaceholder(placeholder), WithFilters(filters), WithValidators(validators...))
 }
 
 // RegisterFilter adds a new filter for a specific match mode to the Filter.
 // Parameters:
 //
 //	matchMode: The match mode for which to register the filter.
-//	filter: The filter to be registered for the specified match mode.
-func (f *Filter) RegisterFilter(matchMode filter.MatchMode, filter filter.Filter) {
-	f.filters[matchMode] = filter
+//	matchFilter: The filter to be registered for the specified match mode.
+func (f *Filter) RegisterFilter(matchMode filter.MatchMode, matchFilter filter.Filter) {
+	f.RegisterRenderer(matchMode, filter.AsRenderer(matchFilter))
+}
+
+// RegisterRenderer adds a new filter implementing the stateless filter.Renderer contract for a specific match mode.
+// Parameters:
+//
+//	matchMode: The match mode for which to register the filter.
+//	renderer: The filter to be registered for the specified match mode.
+func (f *Filter) RegisterRenderer(matchMode filter.MatchMode, renderer filter.Renderer) {
+	f.filters[matchMode] = renderer
+}
+
+// HasFilter reports whether a filter is registered for the given match mode.
+// Parameters:
+//
+//	matchMode: The match mode to look up.
+//
+// Returns:
+//
+//	true if a filter is registered for the match mode; otherwise, false.
+func (f *Filter) HasFilter(matchMode filter.MatchMode) bool {
+	_, ok := f.filters[matchMode]
+	return ok
 }
 
 // RegisterColumnValidator adds a new column validator to the Filter's list of validators.
@@ -90,8 +124,70 @@ func (f *Filter) RegisterColumnValidator(validator column.Validator) {
 	f.columnValidators = append(f.columnValidators, validator)
 }
 
+// RegisterSpecValidator adds a new spec validator to the Filter's list of spec validators.
+// Spec validators run with the column validators and see the match mode and values of every constraint.
+// Parameters:
+//
+//	validator: A spec.Validator to be applied to every constraint.
+func (f *Filter) RegisterSpecValidator(validator spec.Validator) {
+	f.specValidators = append(f.specValidators, validator)
+}
+
+// RegisterColumns adds the configuration of filterable fields, e.g. derived from a model with column.FromStruct.
+// Once columns are registered, only the configured fields pass column validation,
+// and every field is rendered as its configured SQL column.
+// Parameters:
+//
+//	columns: The configuration of the fields, keyed by field name.
+func (f *Filter) RegisterColumns(columns column.Configs) {
+	if f.columns == nil {
+		f.columns = make(column.Configs, len(columns))
+	}
+	maps.Copy(f.columns, columns)
+}
+
+// Dialect returns the dialect of the database set with WithDialect, or the zero Dialect.
+func (f *Filter) Dialect() dialect.Dialect {
+	return f.dialect
+}
+
+// Placeholder returns the placeholder used in SQL conditions.
+func (f *Filter) Placeholder() placeholder.Placeholder {
+	return f.placeholder
+}
+
+// Columns returns a copy of the configuration of the filterable fields registered with RegisterColumns.
+func (f *Filter) Columns() column.Configs {
+	return cloneColumns(f.columns)
+}
+
+// RegisterColumnModes restricts the match modes that can be used on a column,
+// e.g. to keep a leading-wildcard "contains" away from an indexed ID column.
+// Columns without registered modes allow every registered match mode.
+// Parameters:
+//
+//	column: The name of the column, as sent by the client.
+//	modes: The match modes allowed on the column.
+func (f *Filter) RegisterColumnModes(column string, modes ...filter.MatchMode) {
+	if f.columnModes == nil {
+		f.columnModes = make(map[string][]filter.MatchMode)
+	}
+	f.columnModes[column] = modes
+}
+
+// RegisterRewriter adds a rewriter that is applied to every filter tree before it is rendered.
+// Rewriters are called for every node of the tree, bottom-up, and can replace or remove nodes,
+// e.g. to map column names, inject conditions or log what is being filtered.
+// Parameters:
+//
+//	rewriter: The function returning the replacement of a node.
+func (f *Filter) RegisterRewriter(rewriter expr.RewriteFunc) {
+	f.rewriters = append(f.rewriters, rewriter)
+}
+
 // Sql generates an SQL condition string and associated values based on the provided Specs.
-// It constructs a WHERE clause and prepares the corresponding values for parameterized queries.
+// The Specs are converted into a filter tree with Specs.Expr and rendered with SqlExpr,
+// so the registered rewriters apply to them as well.
 //
 // Parameters:
 //
@@ -104,25 +200,115 @@ func (f *Filter) RegisterColumnValidator(validator column.Validator) {
 //	condition: The SQL WHERE clause condition string composed of the conditions derived from specs.
 //	err: An error, if any, encountered during the process.
 func (f *Filter) Sql(specs Specs) (vals []any, condition string, err error) {
-	for _, s := range specs.iter() {
-		if _, ok := f.filters[s.MatchMode]; !ok {
-			err = fmt.Errorf("match mode not registered [%s]", s.MatchMode)
-			return
-		}
+	return f.SqlExprContext(context.Background(), specs.Expr())
+}
+
+// SqlContext generates an SQL condition string and associated values based on the provided Specs,
+// ANDed with the mandatory predicates for the context, as SqlExprContext.
+//
+// Parameters:
+//
+//	ctx: The context of the request, passed to the registered predicates and context-aware filters.
+//	specs: The Specs object that provides the specifications for generating the SQL conditions.
+//	opts: Request settings added to the context, e.g. WithLocation.
+//
+// Returns:
+//
+//	vals: A slice of values that correspond to the placeholders in the SQL condition.
+//	condition: The SQL WHERE clause condition string.
+//	err: An error, if any, encountered during the process.
+func (f *Filter) SqlContext(ctx context.Context, specs Specs, opts ...RequestOption) (vals []any, condition string, err error) {
+	return f.SqlExprContext(ctx, specs.Expr(), opts...)
+}
+
+// SqlExpr generates an SQL condition string and associated values from a filter tree.
+// Groups are wrapped in parentheses and placeholders are numbered in the order they appear,
+// so nested AND/OR/NOT combinations render with the correct precedence.
+// Conditions with a nil value are skipped, as are groups left without any condition.
+//
+// Parameters:
+//
+//	node: The root node of the filter tree, e.g. parsed with expr.Parse or built from Specs.Expr.
+//
+// Returns:
+//
+//	vals: A slice of values that correspond to the placeholders in the SQL condition.
+//	condition: The SQL WHERE clause condition string.
+//	err: An error if the filter exceeds the limits (a *LimitError), a match mode is not registered
+//	     or not allowed on its column (a *MatchModeError),
+//	     an operator is unknown or a value could not be enriched.
+func (f *Filter) SqlExpr(node expr.Node) (vals []any, condition string, err error) {
+	return f.SqlExprContext(context.Background(), node)
+}
+
+// SqlExprContext generates an SQL condition string and associated values from a filter tree,
+// ANDed with the mandatory predicates for the context.
+// The columns of the filter tree are validated with ValidateExpr, and the tree is checked and rewritten
+// before the mandatory predicates are added and is rendered in parentheses, so the client can neither
+// negate nor bypass them.
+// Filters implementing filter.ContextFilter receive the context, including the request settings of opts.
+// Registered predicates and context-aware filters see a background context when called through Sql or SqlExpr.
+//
+// Parameters:
+//
+//	ctx: The context of the request, passed to the registered predicates and context-aware filters.
+//	node: The root node of the filter tree.
+//	opts: Request settings added to the context, e.g. WithLocation.
+//
+// Returns:
+//
+//	vals: A slice of values that correspond to the placeholders in the SQL condition.
+//	condition: The SQL WHERE clause condition string.
+//	err: An error as returned by SqlExpr, a *ValidationError for an invalid condition,
+//	     or the error of a failing predicate.
+func (f *Filter) SqlExprContext(ctx context.Context, node expr.Node, opts ...RequestOption) (vals []any, condition string, err error) {
+	r, condition, err := f.render(ctx, node, opts)
+	if err != nil {
+		return nil, "", err
 	}
+	return r.vals, condition, nil
+}
 
-	var conditions []string
-	for vc, iterErr := range f.conditionsIter(specs) {
-		if iterErr != nil {
-			err = iterErr
-			return
-		}
-		conditions = append(conditions, f.buildSqlCondition(len(vc.values), len(vals)+1, vc.conditions))
-		vals = append(vals, vc.values...)
+// render validates, rewrites and scopes a filter tree and renders it, returning the renderer holding its values.
+func (f *Filter) render(ctx context.Context, node expr.Node, opts []RequestOption) (*sqlRenderer, string, error) {
+	ctx = withRequest(ctx, opts)
+
+	// columns are rendered into the SQL as they are, so they are validated before anything else
+	if err := f.ValidateExpr(node); err != nil {
+		return nil, "", err
+	}
+
+	if err := f.ValidateLimits(node); err != nil {
+		return nil, "", err
+	}
+
+	if err := f.ValidateModes(node); err != nil {
+		return nil, "", err
+	}
+
+	sensitive := f.sensitiveColumns()
+	node, err := f.rewriteContext(ctx, node, sensitive)
+	if err != nil {
+		return nil, "", err
 	}
 
-	condition = strings.Join(conditions, " and ")
-	return
+	node, err = f.Scope(ctx, node)
+	if err != nil {
+		return nil, "", err
+	}
+
+	markers, _ := ctx.Value(markersKey{}).(bool)
+	r := &sqlRenderer{ctx: ctx, filter: f, markers: markers, sensitive: sensitive}
+	condition, err := r.render(node, true)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// filters can bind more values than a condition has, e.g. a date range per day, and predicates bind values too
+	if exceeds(f.limits.MaxParameters, len(r.vals)) {
+		return nil, "", &LimitError{Limit: "MaxParameters", Max: f.limits.MaxParameters, Actual: len(r.vals)}
+	}
+	return r, condition, nil
 }
 
 // ValidateColumns checks if the columns specified in the Specs are valid according to the registered column validators.
@@ -134,92 +320,199 @@ func (f *Filter) Sql(specs Specs) (vals []any, condition string, err error) {
 //
 //	error: Returns an error if any of the columns are invalid or if a validation error occurs. Returns nil if all columns are valid.
 func (f *Filter) ValidateColumns(specs Specs) error {
-	if f.columnValidators == nil {
-		return nil
+	return f.ValidateExpr(specs.Expr())
+}
+
+// ValidateExpr checks if the conditions of a filter tree are valid according to the registered
+// column validators and spec validators. Spec validators are not applied to conditions with a nil value.
+// Once columns are registered, only the configured fields are valid; otherwise, fields must be
+// plain identifiers such as "name" or "country.name", as they are rendered into the SQL.
+// Parameters:
+//
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	error: Returns a *ValidationError for the first invalid condition. Returns nil if all conditions are valid.
+func (f *Filter) ValidateExpr(node expr.Node) error {
+	validators := append(column.Validators{identifierValidator{}}, f.columnValidators...)
+	if len(f.columns) > 0 {
+		validators[0] = f.columns
 	}
 
-	for col, _ := range specs.iter() {
-		for validator, err := range f.columnValidators.Iter(col) {
+	for _, c := range expr.Conditions(node) {
+		for validator, err := range validators.Iter(c.Column) {
 			if err != nil {
-				return fmt.Errorf("%s: %s", validator, err.Error())
+				return &ValidationError{Validator: validator, Column: c.Column, MatchMode: c.MatchMode, Err: err}
 			}
 		}
-	}
 
+		if c.Value == nil {
+			continue
+		}
+
+		s := filter.Spec{Value: c.Value, MatchMode: c.MatchMode}
+		for validator, err := range f.specValidators.Iter(c.Column, s, c.Values()) {
+			if err != nil {
+				return &ValidationError{Validator: validator, Column: c.Column, MatchMode: c.MatchMode, Err: err}
+			}
+		}
+	}
 	return nil
 }
 
-func (f *Filter) buildSqlCondition(totalValsInSpec, valIndex int, conditions []filter.Condition) string {
-	sb := strings.Builder{}
-	sb.WriteString("(")
-	i := 0
-	for opr, c := range f.sqlIter(totalValsInSpec, valIndex, conditions) {
-		sb.WriteString(c)
-		if i < len(conditions)-1 {
-			sb.WriteString(" ")
-			sb.WriteString(opr)
-			sb.WriteString(" ")
+// ValidateModes checks that every condition of a filter tree uses a match mode allowed on its column,
+// as registered with RegisterColumnModes or configured with RegisterColumns.
+// Conditions with a nil value are not checked, since they are not rendered.
+// Parameters:
+//
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	A *MatchModeError for the first condition using a match mode that is not allowed; otherwise, nil.
+func (f *Filter) ValidateModes(node expr.Node) error {
+	for _, c := range expr.Conditions(node) {
+		if c.Value == nil {
+			continue
+		}
+		allowed := f.allowedModes(c.Column)
+		if len(allowed) > 0 && !slices.Contains(allowed, c.MatchMode) {
+			return &MatchModeError{Column: c.Column, MatchMode: c.MatchMode, Allowed: allowed}
 		}
-		i++
 	}
-	sb.WriteString(")")
-	return sb.String()
+	return nil
 }
 
-type valuesConditionComposite struct {
-	values     []any
-	conditions []filter.Condition
+func (f *Filter) allowedModes(col string) []filter.MatchMode {
+	if modes, ok := f.columnModes[col]; ok {
+		return modes
+	}
+	return f.columns[col].Modes
 }
 
-func (f *Filter) conditionsIter(ps Specs) iter.Seq2[*valuesConditionComposite, error] {
-	return func(yield func(*valuesConditionComposite, error) bool) {
-		for col, specs := range ps {
-			vals, condition, err := f.enrichAndExtract(col, specs)
-			if len(specs) == 0 || len(condition) == 0 {
-				continue
-			}
-			if !yield(&valuesConditionComposite{vals, condition}, err) {
-				return
-			}
+// RewriteContext applies the registered rewriters to a filter tree, followed by the ones carried by the context,
+// as added with WithRewriters.
+// Parameters:
+//
+//	ctx: The context of the query.
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	The rewritten tree, or the first error returned by a rewriter.
+func (f *Filter) RewriteContext(ctx context.Context, node expr.Node) (expr.Node, error) {
+	return f.rewriteContext(ctx, node, nil)
+}
+
+// rewriteContext applies the rewriters as RewriteContext, adding to sensitive the columns that the rewriters
+// derive from sensitive columns, so their values are still redacted once the fields are mapped to other columns.
+func (f *Filter) rewriteContext(ctx context.Context, node expr.Node, sensitive map[string]bool) (expr.Node, error) {
+	request, _ := ctx.Value(rewritersKey{}).([]expr.RewriteFunc)
+
+	var err error
+	for _, rewriter := range slices.Concat(f.rewriters, request) {
+		if len(sensitive) > 0 {
+			rewriter = trackSensitive(rewriter, sensitive)
+		}
+		node, err = expr.Rewrite(node, rewriter)
+		if err != nil {
+			return nil, err
 		}
 	}
+	return node, nil
 }
 
-func (f *Filter) sqlIter(totalVals, lastIndex int, conditions []filter.Condition) iter.Seq2[string, string] {
-	return func(yield func(string, string) bool) {
-		ph := f.placeholder
-		for _, condition := range conditions {
-			if !yield(condition.Operator, condition.Filter.Apply(condition.Column, totalVals, lastIndex, ph)) {
-				return
-			}
-			if f.placeholder.Numbered() {
-				lastIndex++
+// trackSensitive wraps a rewriter, marking as sensitive the columns it introduces in place of a node holding sensitive columns.
+func trackSensitive(rewriter expr.RewriteFunc, sensitive map[string]bool) expr.RewriteFunc {
+	return func(n expr.Node) (expr.Node, error) {
+		// rewriters may change the conditions in place, so their columns are read first
+		columns, tainted := make(map[string]bool), false
+		for _, c := range expr.Conditions(n) {
+			columns[c.Column] = true
+			tainted = tainted || sensitive[c.Column]
+		}
+
+		rewritten, err := rewriter(n)
+		if tainted {
+			for _, c := range expr.Conditions(rewritten) {
+				if !columns[c.Column] {
+					sensitive[c.Column] = true
+				}
 			}
 		}
+		return rewritten, err
 	}
 }
 
-func (f *Filter) enrichAndExtract(col string, specs []filter.Spec) (values []any, conditions []filter.Condition, err error) {
-	for _, spec := range specs {
-		if spec.Value == nil {
+// sensitiveColumns returns the fields configured as sensitive and their columns.
+func (f *Filter) sensitiveColumns() map[string]bool {
+	var sensitive map[string]bool
+	for field, config := range f.columns {
+		if !config.Sensitive {
 			continue
 		}
-		condition := filter.Condition{
-			Column:   col,
-			Filter:   f.filters[spec.MatchMode],
-			Operator: spec.Operator,
-		}
-		conditions = append(conditions, condition)
-		err = condition.Filter.EnrichValue(&spec.Value)
-		if err != nil {
-			return
+		if sensitive == nil {
+			sensitive = make(map[string]bool)
 		}
-		if _, ok := spec.Value.([]any); ok {
-			values = append(values, spec.Value.([]any)...)
-		} else {
-			values = append(values, spec.Value)
+		sensitive[field] = true
+		if config.Column != "" {
+			sensitive[config.Column] = true
 		}
 	}
+	return sensitive
+}
+
+type rewritersKey struct{}
+
+// WithRewriters returns a copy of ctx carrying rewriters for a single request, applied after the registered ones,
+// in addition to the ones already carried by ctx. As the tree is validated before it is rewritten,
+// they can map the fields sent by the client to columns that would not pass validation, e.g. quoted columns.
+// Parameters:
+//
+//	ctx: The context of the request.
+//	rewriters: The functions returning the replacement of a node.
+//
+// Returns:
+//
+//	The derived context.
+func WithRewriters(ctx context.Context, rewriters ...expr.RewriteFunc) context.Context {
+	existing, _ := ctx.Value(rewritersKey{}).([]expr.RewriteFunc)
+	return context.WithValue(ctx, rewritersKey{}, append(existing[:len(existing):len(existing)], rewriters...))
+}
 
-	return
+type markersKey struct{}
+
+// WithMarkers returns a copy of ctx rendering conditions with the "?" markers of filter.Bind instead of
+// the placeholder of the Filter, and literal question marks escaped as "??". Adapters binding the values
+// themselves use it to renumber the placeholders, e.g. with filter.Bind, without mistaking a literal
+// question mark for a placeholder.
+// Parameters:
+//
+//	ctx: The context of the request.
+//
+// Returns:
+//
+//	The derived context.
+func WithMarkers(ctx context.Context) context.Context {
+	return context.WithValue(ctx, markersKey{}, true)
+}
+
+// Rewrite applies the registered rewriters to a filter tree, in the order they were registered.
+// Parameters:
+//
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	The rewritten tree, or the first error returned by a rewriter.
+func (f *Filter) Rewrite(node expr.Node) (expr.Node, error) {
+	var err error
+	for _, rewriter := range f.rewriters {
+		node, err = expr.Rewrite(node, rewriter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
 }
@@ -10,7 +10,7 @@ import (
 )
 
 func TestNew(t *testing.T) {
-	pf := New(placeholder.UnNumbered("?"))
+	pf := New(WithPlaceholder(placeholder.UnNumbered("?")))
 	if pf == nil {
 		t.Fatal("New returned nil")
 	}
@@ -31,7 +31,7 @@ func TestNewWithFiltersAndValidators(t *testing.T) {
 }
 
 func TestRegisterFilter(t *testing.T) {
-	pf := New(placeholder.UnNumbered("?"))
+	pf := New(WithPlaceholder(placeholder.UnNumbered("?")))
 	pf.RegisterFilter("equals", filters.ValueFilter("="))
 
 	if _, ok := pf.filters["equals"]; !ok {
@@ -40,7 +40,7 @@ func TestRegisterFilter(t *testing.T) {
 }
 
 func TestRegisterColumnValidator(t *testing.T) {
-	pf := New(placeholder.UnNumbered("?"))
+	pf := New(WithPlaceholder(placeholder.UnNumbered("?")))
 	pf.RegisterColumnValidator(column.AllowedValidator{"name"})
 
 	if len(pf.columnValidators) == 0 {
@@ -68,7 +68,7 @@ func TestSql(t *testing.T) {
 		},
 	}
 
-	pf := New(placeholder.UnNumbered("?"))
+	pf := New(WithPlaceholder(placeholder.UnNumbered("?")))
 	pf.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
 	pf.RegisterFilter(filter.ENDS_WITH, filters.NewPatternMatchFilter("LIKE", filters.PRE))
 
@@ -103,7 +103,7 @@ func TestValidateColumns(t *testing.T) {
 		},
 	}
 
-	pf := New(placeholder.UnNumbered("?"))
+	pf := New(WithPlaceholder(placeholder.UnNumbered("?")))
 	pf.RegisterColumnValidator(column.AllowedValidator{"name"})
 
 	err := pf.ValidateColumns(specs)
@@ -123,7 +123,7 @@ func TestValidateColumnsWithInvalidData(t *testing.T) {
 		},
 	}
 
-	pf := New(placeholder.UnNumbered("?"))
+	pf := New(WithPlaceholder(placeholder.UnNumbered("?")))
 	pf.RegisterColumnValidator(column.AllowedValidator{"name"})
 
 	err := pf.ValidateColumns(specs)
@@ -139,7 +139,7 @@ func TestValidateColumnsWithInvalidData(t *testing.T) {
 
 func TestSqlWithEmptySpecs(t *testing.T) {
 	specs := Specs{}
-	pf := New(placeholder.UnNumbered("?"))
+	pf := New(WithPlaceholder(placeholder.UnNumbered("?")))
 
 	vals, condition, err := pf.Sql(specs)
 	if err != nil {
@@ -0,0 +1,155 @@
+package prime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/AdamShannag/goprime/filter"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Normalize returns the canonical form of the Specs, so semantically identical Specs are equal:
+// operators and match modes are trimmed, operators are lowercased and set on every constraint
+// of a column, and constraints with a nil value are removed as they are not applied.
+// Fields and values are left untouched, as their whitespace is significant: fields are rendered as they are,
+// so " name" and "name" are different columns. The constraints of a column keep their order, as it is
+// the order their placeholders are rendered in.
+// Normalize does not validate the Specs.
+//
+// Returns:
+//
+//	The normalized Specs, nil if no constraint has a value.
+func (s Specs) Normalize() Specs {
+	var normalized Specs
+	for _, key := range slices.Sorted(maps.Keys(s)) {
+		specs := s[key]
+		if len(specs) == 0 {
+			continue
+		}
+
+		op := string(operator(strings.TrimSpace(specs[0].Operator)))
+		var constraints []filter.Spec
+		for _, spec := range specs {
+			if spec.Value == nil {
+				continue
+			}
+			constraints = append(constraints, filter.Spec{
+				Value:     spec.Value,
+				MatchMode: filter.MatchMode(strings.TrimSpace(string(spec.MatchMode))),
+				Operator:  op,
+			})
+		}
+		if len(constraints) == 0 {
+			continue
+		}
+		if normalized == nil {
+			normalized = make(Specs)
+		}
+		normalized[key] = constraints
+	}
+	return normalized
+}
+
+// Normalize returns the canonical form of the event, so semantically identical events are equal:
+// the filters are normalized with Specs.Normalize, the sort order is moved to MultiSortMeta with orders of 1 or -1,
+// and an empty global filter is removed. Sort fields are left untouched, as Specs.Normalize leaves fields.
+//
+// Returns:
+//
+//	The normalized event.
+func (e LazyLoadEvent) Normalize() LazyLoadEvent {
+	normalized := LazyLoadEvent{First: e.First, Rows: e.Rows, Filters: e.Filters.Normalize(), GlobalFilter: e.GlobalFilter}
+	for _, sort := range e.Sorts() {
+		order := 1
+		if sort.Order < 0 {
+			order = -1
+		}
+		normalized.MultiSortMeta = append(normalized.MultiSortMeta, SortMeta{Field: sort.Field, Order: order})
+	}
+	if global, ok := e.GlobalFilter.(string); ok && global == "" {
+		normalized.GlobalFilter = nil
+	}
+	return normalized
+}
+
+// Hash returns a stable hash of the normalized event, e.g. to key a cache of result pages.
+// Semantically identical events have the same hash, whatever the order of their fields or the case of their operators.
+// Values are compared by their JSON encoding, so 1 and 1.0 are equal.
+//
+// Returns:
+//
+//	The hex-encoded SHA-256 hash, or an error if a value cannot be encoded as JSON.
+func (e LazyLoadEvent) Hash() (string, error) {
+	// encoding/json sorts the keys of maps, so the encoding of a normalized event is canonical
+	b, err := json.Marshal(e.Normalize())
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Shape describes the query pattern of the normalized event, ignoring its values: the filtered fields
+// with their operator, match modes and number of values, the sort order, and whether the event has a
+// global filter or is paged, e.g.
+//
+//	"country.name" and "in"(3); "name" or "contains" "startsWith" | sort "date" desc | global | paged
+//
+// Events with the same shape render the same condition, so it can group metrics or key prepared statements.
+// The page itself is not part of the shape; bind it when preparing statements.
+//
+// Returns:
+//
+//	The shape, empty if the event neither filters, sorts nor pages.
+func (e LazyLoadEvent) Shape() string {
+	normalized := e.Normalize()
+
+	var parts []string
+	if len(normalized.Filters) > 0 {
+		var columns []string
+		for _, field := range slices.Sorted(maps.Keys(normalized.Filters)) {
+			specs := normalized.Filters[field]
+			column := strconv.Quote(field) + " " + specs[0].Operator
+			for _, spec := range specs {
+				column += " " + strconv.Quote(string(spec.MatchMode))
+				if values, ok := spec.Value.([]any); ok {
+					column += "(" + strconv.Itoa(len(values)) + ")"
+				}
+			}
+			columns = append(columns, column)
+		}
+		parts = append(parts, strings.Join(columns, "; "))
+	}
+
+	if len(normalized.MultiSortMeta) > 0 {
+		sorts := make([]string, len(normalized.MultiSortMeta))
+		for i, sort := range normalized.MultiSortMeta {
+			sorts[i] = strconv.Quote(sort.Field) + " asc"
+			if sort.Order < 0 {
+				sorts[i] = strconv.Quote(sort.Field) + " desc"
+			}
+		}
+		parts = append(parts, "sort "+strings.Join(sorts, ", "))
+	}
+
+	if normalized.GlobalFilter != nil {
+		parts = append(parts, "global")
+	}
+	if normalized.First > 0 || normalized.Rows > 0 {
+		parts = append(parts, "paged")
+	}
+	return strings.Join(parts, " | ")
+}
+
+// Fingerprint returns a stable hash of the Shape of the event, to use as a compact label of its query pattern.
+//
+// Returns:
+//
+//	The hex-encoded SHA-256 hash of the shape.
+func (e LazyLoadEvent) Fingerprint() string {
+	sum := sha256.Sum256([]byte(e.Shape()))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,162 @@
+package prime
+
+import (
+	"encoding/json"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	specs := Specs{
+		" name ": {
+			{Value: "Ja", MatchMode: " startsWith", Operator: " OR"},
+			{Value: "Bob", MatchMode: filter.EQUALS},
+			{Value: nil, MatchMode: filter.CONTAINS},
+		},
+		"name":   {{Value: "Al", MatchMode: filter.EQUALS}},
+		"status": {{Value: nil, MatchMode: filter.EQUALS}},
+		"empty":  {},
+	}
+
+	// fields are rendered as they are, so " name " and "name" are different columns
+	expected := Specs{
+		"name": {{Value: "Al", MatchMode: filter.EQUALS, Operator: "and"}},
+		" name ": {
+			{Value: "Ja", MatchMode: filter.STARTS_WITH, Operator: "or"},
+			{Value: "Bob", MatchMode: filter.EQUALS, Operator: "or"},
+		},
+	}
+	if normalized := specs.Normalize(); !reflect.DeepEqual(normalized, expected) {
+		t.Errorf("expected %v, got %v", expected, normalized)
+	}
+
+	if normalized := (Specs{"status": {{MatchMode: filter.EQUALS}}}).Normalize(); normalized != nil {
+		t.Errorf("expected nil Specs, got %v", normalized)
+	}
+}
+
+func TestNormalizeEvent(t *testing.T) {
+	event := LazyLoadEvent{First: 10, Rows: 10, SortField: " date ", SortOrder: -5, GlobalFilter: ""}
+
+	normalized := event.Normalize()
+	expected := LazyLoadEvent{First: 10, Rows: 10, MultiSortMeta: []SortMeta{{Field: " date ", Order: -1}}}
+	if !reflect.DeepEqual(normalized, expected) {
+		t.Errorf("expected %+v, got %+v", expected, normalized)
+	}
+}
+
+func TestHash(t *testing.T) {
+	var a, b LazyLoadEvent
+	if err := json.Unmarshal([]byte(`{"first":0,"rows":10,"sortField":"date","sortOrder":1,
+		"filters":{"name":[{"value":"Ja","matchMode":"startsWith","operator":"and"}],"id":[{"value":[1,2],"matchMode":"in","operator":"and"}]}}`), &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"rows":10,"multiSortMeta":[{"field":"date","order":1}],
+		"filters":{"id":[{"value":[1.0,2],"matchMode":"in","operator":"AND "}],"status":[{"value":null,"matchMode":"equals"}],"name":[{"value":"Ja","matchMode":"startsWith"}]}}`), &b); err != nil {
+		t.Fatal(err)
+	}
+
+	hashA, err := a.Hash()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	hashB, err := b.Hash()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if hashA != hashB {
+		t.Errorf("expected equal hashes for equivalent events, got %s and %s", hashA, hashB)
+	}
+	if len(hashA) != 64 {
+		t.Errorf("expected a hex-encoded SHA-256 hash, got %s", hashA)
+	}
+
+	b.Filters["name"][0].Value = "Ja "
+	if hashC, _ := b.Hash(); hashC == hashA {
+		t.Error("expected different values to change the hash")
+	}
+
+	b.GlobalFilter = make(chan int)
+	if _, err := b.Hash(); err == nil {
+		t.Error("expected an error for a value that cannot be encoded, got nil")
+	}
+}
+
+func TestShape(t *testing.T) {
+	event := LazyLoadEvent{
+		Rows: 10,
+		Filters: Specs{
+			"name": {
+				{Value: "Ja", MatchMode: filter.STARTS_WITH, Operator: "OR"},
+				{Value: "B", MatchMode: filter.CONTAINS},
+			},
+			"country.name": {{Value: []any{"DE", "FR", "US"}, MatchMode: filter.IN}},
+		},
+		MultiSortMeta: []SortMeta{{Field: "date", Order: -1}, {Field: "name", Order: 1}},
+		GlobalFilter:  "x",
+	}
+
+	expected := `"country.name" and "in"(3); "name" or "startsWith" "contains" | sort "date" desc, "name" asc | global | paged`
+	if shape := event.Shape(); shape != expected {
+		t.Errorf("expected shape %s, got %s", expected, shape)
+	}
+
+	other := event
+	other.First = 20
+	other.GlobalFilter = "y"
+	other.Filters = Specs{
+		"name": {
+			{Value: "Al", MatchMode: filter.STARTS_WITH, Operator: "or"},
+			{Value: "Bob", MatchMode: filter.CONTAINS},
+		},
+		"country.name": {{Value: []any{"IT", "ES", "PT"}, MatchMode: filter.IN}},
+	}
+	if event.Fingerprint() != other.Fingerprint() {
+		t.Errorf("expected equal fingerprints for events differing in values, got %s and %s", event.Shape(), other.Shape())
+	}
+
+	other.Filters["country.name"][0].Value = []any{"IT"}
+	if event.Fingerprint() == other.Fingerprint() {
+		t.Error("expected the number of values to change the fingerprint")
+	}
+
+	if shape := (LazyLoadEvent{}).Shape(); shape != "" {
+		t.Errorf("expected an empty shape, got %s", shape)
+	}
+}
+
+func TestShapeRendersSameSql(t *testing.T) {
+	f := New(WithDialect(dialect.Postgres), WithFilters(map[filter.MatchMode]filter.Filter{
+		filter.EQUALS:       filters.ValueFilter("="),
+		filter.GREATER_THAN: filters.ValueFilter(">"),
+	}))
+	event := func(first, second filter.Spec) LazyLoadEvent {
+		return LazyLoadEvent{Filters: Specs{"age": {first, second}}}
+	}
+	equals := func(v any) filter.Spec { return filter.Spec{Value: v, MatchMode: filter.EQUALS} }
+	gt := func(v any) filter.Spec { return filter.Spec{Value: v, MatchMode: filter.GREATER_THAN} }
+
+	a, b := event(equals(5), gt(3)), event(equals(1), gt(30))
+	if a.Shape() != b.Shape() {
+		t.Fatalf("expected equal shapes, got %s and %s", a.Shape(), b.Shape())
+	}
+	_, conditionA, err := f.Sql(a.Filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, conditionB, err := f.Sql(b.Filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conditionA != conditionB {
+		t.Errorf("expected events with equal shapes to render the same SQL, got %s and %s", conditionA, conditionB)
+	}
+
+	// the order of the constraints decides the order of the placeholders
+	if reordered := event(gt(3), equals(5)); reordered.Shape() == a.Shape() {
+		t.Errorf("expected reordered constraints to change the shape, got %s", reordered.Shape())
+	}
+}
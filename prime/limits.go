@@ -0,0 +1,141 @@
+package prime
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/expr"
+	"unicode/utf8"
+)
+
+// Limits bounds the complexity of the filters a client can send. A zero value disables a limit.
+type Limits struct {
+	MaxConditions           int // Maximum number of conditions in a filter
+	MaxConstraintsPerColumn int // Maximum number of conditions on a single column
+	MaxListValues           int // Maximum number of values of a single condition, e.g. an IN list
+	MaxStringLength         int // Maximum number of characters of a string value
+	MaxParameters           int // Maximum number of values bound in the generated condition, including those of predicates
+	MaxDepth                int // Maximum nesting depth of a filter tree
+}
+
+// DefaultLimits returns sane limits for a dialect: generous enough for any PrimeNG table,
+// while keeping the generated statement within the parameter limits of the database.
+// Parameters:
+//
+//	d: The dialect of the database, e.g. dialect.SQLServer, which binds at most 2100 values.
+//
+// Returns:
+//
+//	The Limits for the dialect.
+func DefaultLimits(d dialect.Dialect) Limits {
+	limits := Limits{
+		MaxConditions:           100,
+		MaxConstraintsPerColumn: 10,
+		MaxListValues:           1000,
+		MaxStringLength:         1000,
+		MaxParameters:           d.MaxParameters,
+		MaxDepth:                10,
+	}
+	if d.MaxListValues > 0 {
+		limits.MaxListValues = min(limits.MaxListValues, d.MaxListValues)
+	}
+	if d.MaxParameters > 0 {
+		limits.MaxListValues = min(limits.MaxListValues, d.MaxParameters)
+	}
+	return limits
+}
+
+// LimitError is returned when a filter exceeds one of the configured Limits.
+type LimitError struct {
+	Limit  string // Name of the exceeded limit, as named in Limits, e.g. "MaxListValues"
+	Column string // Column the limit was exceeded on, if it applies to a single column
+	Max    int    // Configured limit
+	Actual int    // Actual value that exceeded the limit
+}
+
+func (e *LimitError) Error() string {
+	if e.Column != "" {
+		return fmt.Sprintf("limit [%s] exceeded on column [%s]: %d > %d", e.Limit, e.Column, e.Actual, e.Max)
+	}
+	return fmt.Sprintf("limit [%s] exceeded: %d > %d", e.Limit, e.Actual, e.Max)
+}
+
+// SetLimits sets the complexity limits enforced when generating conditions.
+// Parameters:
+//
+//	limits: The limits to enforce, e.g. DefaultLimits(dialect.Postgres).
+func (f *Filter) SetLimits(limits Limits) {
+	f.limits = limits
+}
+
+// ValidateLimits checks a filter tree against the configured Limits.
+// Conditions with a nil value are not counted, since they are not rendered.
+// MaxParameters is checked against the values of the conditions, before they are enriched;
+// SqlExprContext checks it again against the values bound in the rendered condition.
+// Parameters:
+//
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	A *LimitError for the first exceeded limit; otherwise, nil.
+func (f *Filter) ValidateLimits(node expr.Node) error {
+	l := f.limits
+	if l == (Limits{}) {
+		return nil
+	}
+
+	if depth := depth(node); exceeds(l.MaxDepth, depth) {
+		return &LimitError{Limit: "MaxDepth", Max: l.MaxDepth, Actual: depth}
+	}
+
+	conditions, parameters := 0, 0
+	perColumn := make(map[string]int)
+	for _, c := range expr.Conditions(node) {
+		if c.Value == nil {
+			continue
+		}
+		values := c.Values()
+
+		conditions++
+		perColumn[c.Column]++
+		parameters += len(values)
+
+		if exceeds(l.MaxConditions, conditions) {
+			return &LimitError{Limit: "MaxConditions", Max: l.MaxConditions, Actual: conditions}
+		}
+		if exceeds(l.MaxConstraintsPerColumn, perColumn[c.Column]) {
+			return &LimitError{Limit: "MaxConstraintsPerColumn", Column: c.Column, Max: l.MaxConstraintsPerColumn, Actual: perColumn[c.Column]}
+		}
+		if exceeds(l.MaxListValues, len(values)) {
+			return &LimitError{Limit: "MaxListValues", Column: c.Column, Max: l.MaxListValues, Actual: len(values)}
+		}
+		if exceeds(l.MaxParameters, parameters) {
+			return &LimitError{Limit: "MaxParameters", Max: l.MaxParameters, Actual: parameters}
+		}
+		for _, v := range values {
+			if s, ok := v.(string); ok && exceeds(l.MaxStringLength, utf8.RuneCountInString(s)) {
+				return &LimitError{Limit: "MaxStringLength", Column: c.Column, Max: l.MaxStringLength, Actual: utf8.RuneCountInString(s)}
+			}
+		}
+	}
+	return nil
+}
+
+func exceeds(limit, actual int) bool {
+	return limit > 0 && actual > limit
+}
+
+func depth(node expr.Node) int {
+	switch n := node.(type) {
+	case *expr.Group:
+		d := 0
+		for _, child := range n.Nodes {
+			d = max(d, depth(child))
+		}
+		return d + 1
+	case *expr.Not:
+		return depth(n.Node) + 1
+	default:
+		return 0
+	}
+}
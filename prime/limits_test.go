@@ -0,0 +1,151 @@
+package prime
+
+import (
+	"context"
+	"errors"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"strings"
+	"testing"
+)
+
+func TestDefaultLimits(t *testing.T) {
+	tests := []struct {
+		dialect       dialect.Dialect
+		maxParameters int
+		maxListValues int
+	}{
+		{dialect.Postgres, 65535, 1000},
+		{dialect.SQLServer, 2100, 1000},
+		{dialect.Oracle, 65535, 1000},
+		{dialect.Dialect{MaxParameters: 100}, 100, 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.dialect.Name, func(t *testing.T) {
+			limits := DefaultLimits(test.dialect)
+			if limits.MaxParameters != test.maxParameters {
+				t.Errorf("expected MaxParameters %d, got %d", test.maxParameters, limits.MaxParameters)
+			}
+			if limits.MaxListValues != test.maxListValues {
+				t.Errorf("expected MaxListValues %d, got %d", test.maxListValues, limits.MaxListValues)
+			}
+		})
+	}
+}
+
+func TestValidateLimits(t *testing.T) {
+	limits := Limits{
+		MaxConditions:           3,
+		MaxConstraintsPerColumn: 2,
+		MaxListValues:           3,
+		MaxStringLength:         5,
+		MaxParameters:           4,
+		MaxDepth:                3,
+	}
+
+	tests := []struct {
+		name   string
+		tree   expr.Node
+		limit  string
+		column string
+	}{
+		{"within limits", expr.And(expr.Cond("name", filter.EQUALS, "James"), expr.Cond("id", filter.IN, []any{1, 2, 3})), "", ""},
+		{"nil values are not counted", expr.And(expr.Cond("a", filter.EQUALS, 1), expr.Cond("a", filter.EQUALS, nil), expr.Cond("a", filter.EQUALS, 2)), "", ""},
+		{"conditions", expr.And(expr.Cond("a", filter.EQUALS, 1), expr.Cond("b", filter.EQUALS, 1), expr.Cond("c", filter.EQUALS, 1), expr.Cond("d", filter.EQUALS, 1)), "MaxConditions", ""},
+		{"constraints per column", expr.Or(expr.Cond("a", filter.EQUALS, 1), expr.Cond("a", filter.EQUALS, 2), expr.Cond("a", filter.EQUALS, 3)), "MaxConstraintsPerColumn", "a"},
+		{"list values", expr.Cond("id", filter.IN, []any{1, 2, 3, 4}), "MaxListValues", "id"},
+		{"string length", expr.Cond("name", filter.EQUALS, "Josephine"), "MaxStringLength", "name"},
+		{"parameters", expr.And(expr.Cond("a", filter.IN, []any{1, 2, 3}), expr.Cond("b", filter.BETWEEN, []any{1, 2})), "MaxParameters", ""},
+		{"depth", expr.And(expr.Or(&expr.Not{Node: expr.And(expr.Cond("a", filter.EQUALS, 1))})), "MaxDepth", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pf := newTreeFilter()
+			pf.SetLimits(limits)
+
+			err := pf.ValidateLimits(test.tree)
+			if test.limit == "" {
+				if err != nil {
+					t.Errorf("expected nil error, got %v", err)
+				}
+				return
+			}
+
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("expected a *LimitError, got %v", err)
+			}
+			if limitErr.Limit != test.limit || limitErr.Column != test.column {
+				t.Errorf("expected limit %s on column %q, got %+v", test.limit, test.column, limitErr)
+			}
+		})
+	}
+}
+
+func TestSqlEnforcesLimits(t *testing.T) {
+	pf := newTreeFilter()
+	pf.SetLimits(DefaultLimits(dialect.SQLServer))
+
+	values := make([]any, 1001)
+	for i := range values {
+		values[i] = i
+	}
+
+	_, _, err := pf.Sql(Specs{"id": {{Value: values, MatchMode: filter.IN}}})
+
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected a *LimitError, got %v", err)
+	}
+
+	expectedErrMsg := "limit [MaxListValues] exceeded on column [id]: 1001 > 1000"
+	if err.Error() != expectedErrMsg {
+		t.Errorf("expected error message '%s', got '%s'", expectedErrMsg, err.Error())
+	}
+
+	_, _, err = pf.Sql(Specs{"name": {{Value: strings.Repeat("a", 10), MatchMode: filter.EQUALS}}})
+	if err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+}
+
+func TestSqlEnforcesMaxParametersAfterRendering(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterFilter(filter.DATE_IS, filters.LocalDateFilter(filter.DATE_IS))
+	pf.RegisterPredicate(tenantPredicate)
+	pf.SetLimits(Limits{MaxParameters: 4})
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	tests := []struct {
+		name   string
+		specs  Specs
+		actual int
+	}{
+		{"enriched values", Specs{"date": {
+			{Value: "2024-08-12T00:00:00Z", MatchMode: filter.DATE_IS, Operator: "or"},
+			{Value: "2024-08-13T00:00:00Z", MatchMode: filter.DATE_IS},
+		}}, 5},
+		{"predicate values", Specs{"id": {{Value: []any{1, 2, 3, 4}, MatchMode: filter.IN}}}, 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := pf.ValidateLimits(test.specs.Expr()); err != nil {
+				t.Fatalf("expected the tree to pass ValidateLimits, got %v", err)
+			}
+
+			_, _, err := pf.SqlContext(ctx, test.specs)
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("expected a *LimitError, got %v", err)
+			}
+			if limitErr.Limit != "MaxParameters" || limitErr.Actual != test.actual {
+				t.Errorf("expected MaxParameters exceeded with %d values, got %+v", test.actual, limitErr)
+			}
+		})
+	}
+}
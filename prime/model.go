@@ -0,0 +1,45 @@
+package prime
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/column"
+	"maps"
+	"slices"
+)
+
+// NewForModel creates a new Filter configured from the prime struct tags of a model, see column.FromStruct.
+// Only the tagged fields can be filtered, and they are rendered as their SQL column; each field allows
+// the match modes of its tag or, without any, the match modes suited to its type, see column.Type.Modes.
+// Only the fields tagged sortable can sort the table. Tags can name custom match modes, as long as their
+// filters are set with the options, e.g. WithFilters or WithRenderers.
+// Parameters:
+//
+//	opts: The options configuring the Filter, e.g. WithDialect(dialect.Postgres) or WithFilters(filters).
+//
+// Returns:
+//
+//	A pointer to a newly created Filter instance, or an error if T is not a struct, a tag is invalid
+//	or a tag names a match mode without a filter.
+func NewForModel[T any](opts ...Option) (*Filter, error) {
+	columns, err := column.FromStruct[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	f := New(opts...)
+	for _, field := range slices.Sorted(maps.Keys(columns)) {
+		config := columns[field]
+		if len(config.Modes) == 0 {
+			config.Modes = config.Type.Modes()
+			columns[field] = config
+			continue
+		}
+		for _, mode := range config.Modes {
+			if _, ok := f.filters[mode]; !ok {
+				return nil, fmt.Errorf("match mode [%s] of field [%s] has no registered filter", mode, field)
+			}
+		}
+	}
+	f.RegisterColumns(columns)
+	return f, nil
+}
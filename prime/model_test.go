@@ -0,0 +1,85 @@
+package prime
+
+import (
+	"errors"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"testing"
+	"time"
+)
+
+type modelCustomer struct {
+	Name     string    `prime:"name,col=c.name,modes=contains|startsWith,sortable"`
+	Activity int       `prime:"activity,col=c.activity"`
+	Date     time.Time `prime:"date,sortable"`
+	Secret   string
+}
+
+func TestNewForModel(t *testing.T) {
+	pf, err := NewForModel[modelCustomer](WithFilters(map[filter.MatchMode]filter.Filter{
+		filter.CONTAINS:     filters.NewPatternMatchFilter("LIKE", filters.AROUND),
+		filter.STARTS_WITH:  filters.NewPatternMatchFilter("LIKE", filters.POST),
+		filter.EQUALS:       filters.ValueFilter("="),
+		filter.GREATER_THAN: filters.ValueFilter(">"),
+	}))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	_, condition, err := pf.Sql(Specs{
+		"name":     {{Value: "Ja", MatchMode: filter.CONTAINS}},
+		"activity": {{Value: 50, MatchMode: filter.GREATER_THAN}},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	expected := "((c.activity > ?)) and ((c.name LIKE ?))"
+	if condition != expected {
+		t.Errorf("expected condition %s, got %s", expected, condition)
+	}
+
+	var modeErr *MatchModeError
+	if _, _, err := pf.Sql(Specs{"name": {{Value: "Ja", MatchMode: filter.EQUALS}}}); !errors.As(err, &modeErr) {
+		t.Errorf("expected a *MatchModeError for a mode outside the tag, got %v", err)
+	}
+	if _, _, err := pf.Sql(Specs{"date": {{Value: "Ja", MatchMode: filter.CONTAINS}}}); !errors.As(err, &modeErr) {
+		t.Errorf("expected a *MatchModeError for a mode outside the type, got %v", err)
+	}
+
+	if _, err := pf.OrderBy([]SortMeta{{Field: "activity", Order: 1}}); err == nil {
+		t.Error("expected an error sorting by a field that is not sortable, got nil")
+	}
+	if orderBy, err := pf.OrderBy([]SortMeta{{Field: "name", Order: -1}}); err != nil || orderBy != "c.name DESC" {
+		t.Errorf("expected c.name DESC, got %s, %v", orderBy, err)
+	}
+}
+
+func TestNewForModelCustomMode(t *testing.T) {
+	type customer struct {
+		Name string `prime:"name,modes=ilike"`
+	}
+
+	pf, err := NewForModel[customer](WithFilters(map[filter.MatchMode]filter.Filter{
+		"ilike": filters.NewPatternMatchFilter("ILIKE", filters.AROUND),
+	}))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	_, condition, err := pf.Sql(Specs{"name": {{Value: "ja", MatchMode: "ilike"}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if expected := "((name ILIKE ?))"; condition != expected {
+		t.Errorf("expected condition %s, got %s", expected, condition)
+	}
+}
+
+func TestNewForModelErrors(t *testing.T) {
+	type invalid struct {
+		Name string `prime:"name,modes=like"`
+	}
+	if _, err := NewForModel[invalid](); err == nil {
+		t.Error("expected an error for a match mode without a filter, got nil")
+	}
+}
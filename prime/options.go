@@ -0,0 +1,98 @@
+package prime
+
+import (
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/spec"
+	"maps"
+)
+
+// Option configures a Filter created with New.
+type Option func(*Filter)
+
+// WithPlaceholder sets the placeholder used in SQL conditions.
+// Parameters:
+//
+//	placeholder: An implementation of the Placeholder interface for SQL conditions.
+func WithPlaceholder(placeholder placeholder.Placeholder) Option {
+	return func(f *Filter) {
+		f.placeholder = placeholder
+	}
+}
+
+// WithDialect sets the dialect of the database and uses its placeholder.
+// It does not set limits; use WithLimits(DefaultLimits(d)) to enforce the limits of the dialect.
+// Parameters:
+//
+//	d: The dialect of the database, e.g. dialect.Postgres.
+func WithDialect(d dialect.Dialect) Option {
+	return func(f *Filter) {
+		f.dialect = d
+		f.placeholder = d.Placeholder
+	}
+}
+
+// WithFilters adds filters for their match modes, replacing the filters already set for them.
+// Parameters:
+//
+//	filters: A map of filter.MatchMode to filter.Filter.
+func WithFilters(filters map[filter.MatchMode]filter.Filter) Option {
+	return func(f *Filter) {
+		for matchMode, matchFilter := range filters {
+			f.filters[matchMode] = filter.AsRenderer(matchFilter)
+		}
+	}
+}
+
+// WithRenderers adds filters implementing the stateless filter.Renderer contract for their match modes,
+// replacing the filters already set for them.
+// Parameters:
+//
+//	renderers: A map of filter.MatchMode to filter.Renderer.
+func WithRenderers(renderers map[filter.MatchMode]filter.Renderer) Option {
+	return func(f *Filter) {
+		maps.Copy(f.filters, renderers)
+	}
+}
+
+// WithValidators adds column validators, as RegisterColumnValidator.
+// Parameters:
+//
+//	validators: The column validators to be applied to every column.
+func WithValidators(validators ...column.Validator) Option {
+	return func(f *Filter) {
+		f.columnValidators = append(f.columnValidators, validators...)
+	}
+}
+
+// WithSpecValidators adds spec validators, as RegisterSpecValidator.
+// Parameters:
+//
+//	validators: The spec validators to be applied to every constraint.
+func WithSpecValidators(validators ...spec.Validator) Option {
+	return func(f *Filter) {
+		f.specValidators = append(f.specValidators, validators...)
+	}
+}
+
+// WithColumns adds the configuration of filterable fields, as RegisterColumns.
+// Parameters:
+//
+//	columns: The configuration of the fields, keyed by field name.
+func WithColumns(columns column.Configs) Option {
+	return func(f *Filter) {
+		f.RegisterColumns(columns)
+	}
+}
+
+// WithLimits sets the complexity limits, as SetLimits.
+// Parameters:
+//
+//	limits: The limits to enforce, e.g. DefaultLimits(dialect.Postgres).
+func WithLimits(limits Limits) Option {
+	return func(f *Filter) {
+		f.limits = limits
+	}
+}
@@ -0,0 +1,90 @@
+package prime
+
+import (
+	"context"
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/spec"
+	"testing"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	pf := New(
+		WithDialect(dialect.SQLServer),
+		WithFilters(map[filter.MatchMode]filter.Filter{
+			filter.EQUALS: filters.ValueFilter("="),
+			filter.IN:     filters.InFilter(0),
+		}),
+		WithColumns(column.Configs{"name": {Field: "name", Column: "c.name", Type: column.STRING}, "tags": {Field: "tags", Type: column.STRING}}),
+		WithValidators(column.AllowedValidator{"name", "tags"}),
+		WithSpecValidators(spec.MaxItemsValidator(2)),
+		WithLimits(Limits{MaxConditions: 2}),
+	)
+
+	vals, condition, err := pf.Sql(Specs{"name": {{Value: "Bob", MatchMode: filter.EQUALS}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if condition != "((c.name = @p1))" || len(vals) != 1 {
+		t.Errorf("expected condition ((c.name = @p1)), got %s", condition)
+	}
+
+	if err = pf.ValidateColumns(Specs{"age": {{Value: 3, MatchMode: filter.EQUALS}}}); err == nil {
+		t.Error("expected an error for a column that is not configured, got nil")
+	}
+
+	if err = pf.ValidateColumns(Specs{"tags": {{Value: []any{"a", "b", "c"}, MatchMode: filter.IN}}}); err == nil {
+		t.Error("expected an error from the spec validator, got nil")
+	}
+
+	specs := Specs{
+		"name": {{Value: "Bob", MatchMode: filter.EQUALS}, {Value: "Amy", MatchMode: filter.EQUALS}},
+		"tags": {{Value: "a", MatchMode: filter.EQUALS}},
+	}
+	var limitErr *LimitError
+	if _, _, err = pf.Sql(specs); !errors.As(err, &limitErr) {
+		t.Errorf("expected a *LimitError, got %v", err)
+	}
+}
+
+func TestNewDefaults(t *testing.T) {
+	pf := New()
+	pf.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+
+	_, condition, err := pf.Sql(Specs{"name": {{Value: "Bob", MatchMode: filter.EQUALS}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if condition != "((name = ?))" {
+		t.Errorf("expected condition ((name = ?)), got %s", condition)
+	}
+}
+
+func TestNewWithFiltersCopiesFilters(t *testing.T) {
+	filtersMap := map[filter.MatchMode]filter.Filter{}
+	pf := NewWithFilters(nil, filtersMap)
+	pf.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+
+	if len(filtersMap) != 0 {
+		t.Errorf("expected the filters of the caller to be left unchanged, got %v", filtersMap)
+	}
+}
+
+func TestNewWithRenderers(t *testing.T) {
+	pf := New(WithRenderers(map[filter.MatchMode]filter.Renderer{
+		filter.IN: filter.RenderFunc(func(_ context.Context, column string, values []any) (string, []any, error) {
+			return "(" + column + " = ANY(?))", []any{values}, nil
+		}),
+	}))
+
+	vals, condition, err := pf.Sql(Specs{"tags": {{Value: []any{"a", "b"}, MatchMode: filter.IN}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if condition != "((tags = ANY(?)))" || len(vals) != 1 {
+		t.Errorf("expected condition ((tags = ANY(?))) with one value, got %s %v", condition, vals)
+	}
+}
@@ -0,0 +1,71 @@
+package prime
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SortError is returned when a table is sorted by a field that cannot be sorted.
+type SortError struct {
+	Field string // Field the table was sorted by
+}
+
+func (e *SortError) Error() string {
+	return fmt.Sprintf("column [%s] is not sortable", e.Field)
+}
+
+// identifier matches the fields that can be used in an ORDER BY clause or a condition without a column configuration.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// identifierValidator allows only plain identifiers, the fields that can be rendered without a column configuration.
+type identifierValidator struct{}
+
+func (identifierValidator) Validate(column string) error {
+	if !identifier.MatchString(column) {
+		return fmt.Errorf("column [%s] is not an identifier", column)
+	}
+	return nil
+}
+
+func (identifierValidator) Name() string {
+	return "IdentifierValidator"
+}
+
+// OrderBy generates the list of an SQL ORDER BY clause from a sort order, e.g. "c.name ASC, date DESC".
+// Fields cannot be bound as values, so they are checked with the registered column validators.
+// Once columns are registered, only the fields configured as sortable are accepted and they are rendered
+// as their SQL column; otherwise, fields must be plain identifiers such as "name" or "country.name".
+// Parameters:
+//
+//	sorts: The sort order, e.g. from LazyLoadEvent.Sorts.
+//
+// Returns:
+//
+//	The ORDER BY list, empty if sorts is empty, a *ValidationError if a column validator
+//	rejects a field, or a *SortError if a field cannot be sorted.
+func (f *Filter) OrderBy(sorts []SortMeta) (string, error) {
+	parts := make([]string, 0, len(sorts))
+	for _, s := range sorts {
+		for validator, err := range f.columnValidators.Iter(s.Field) {
+			if err != nil {
+				return "", &ValidationError{Validator: validator, Column: s.Field, Err: err}
+			}
+		}
+
+		if len(f.columns) > 0 {
+			if !f.columns[s.Field].Sortable {
+				return "", &SortError{Field: s.Field}
+			}
+		} else if !identifier.MatchString(s.Field) {
+			return "", &SortError{Field: s.Field}
+		}
+
+		direction := "ASC"
+		if s.Order < 0 {
+			direction = "DESC"
+		}
+		parts = append(parts, f.columns.Column(s.Field)+" "+direction)
+	}
+	return strings.Join(parts, ", "), nil
+}
@@ -0,0 +1,59 @@
+package prime
+
+import (
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	"testing"
+)
+
+func TestOrderBy(t *testing.T) {
+	pf := newTreeFilter()
+
+	orderBy, err := pf.OrderBy([]SortMeta{{Field: "name", Order: 1}, {Field: "country.name", Order: -1}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if expected := "name ASC, country.name DESC"; orderBy != expected {
+		t.Errorf("expected %s, got %s", expected, orderBy)
+	}
+
+	if orderBy, err = pf.OrderBy(nil); err != nil || orderBy != "" {
+		t.Errorf("expected an empty ORDER BY list, got %s (%v)", orderBy, err)
+	}
+
+	var sortErr *SortError
+	if _, err = pf.OrderBy([]SortMeta{{Field: "name; DROP TABLE users", Order: 1}}); !errors.As(err, &sortErr) {
+		t.Errorf("expected a *SortError, got %v", err)
+	}
+}
+
+func TestOrderByWithColumns(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterColumns(column.Configs{
+		"name":     {Field: "name", Column: "c.name", Type: column.STRING, Sortable: true},
+		"activity": {Field: "activity", Type: column.NUMBER},
+	})
+
+	orderBy, err := pf.OrderBy([]SortMeta{{Field: "name", Order: -1}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if expected := "c.name DESC"; orderBy != expected {
+		t.Errorf("expected %s, got %s", expected, orderBy)
+	}
+
+	var sortErr *SortError
+	if _, err = pf.OrderBy([]SortMeta{{Field: "activity", Order: 1}}); !errors.As(err, &sortErr) || sortErr.Field != "activity" {
+		t.Errorf("expected a *SortError on activity, got %v", err)
+	}
+}
+
+func TestOrderByWithColumnValidator(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterColumnValidator(column.AllowedValidator{"name"})
+
+	var validationErr *ValidationError
+	if _, err := pf.OrderBy([]SortMeta{{Field: "date", Order: 1}}); !errors.As(err, &validationErr) || validationErr.Column != "date" {
+		t.Errorf("expected a *ValidationError on date, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package prime
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"strings"
+)
+
+// sqlRenderer renders a filter tree into an SQL condition, collecting the bound values
+// in the order their placeholders appear.
+type sqlRenderer struct {
+	ctx       context.Context
+	filter    *Filter
+	vals      []any
+	redacted  []bool          // whether each value belongs to a sensitive column
+	sensitive map[string]bool // sensitive columns, see Filter.sensitiveColumns
+	markers   bool            // keep the "?" markers of filter.Bind instead of placeholders, see WithMarkers
+}
+
+func (r *sqlRenderer) render(node expr.Node, root bool) (string, error) {
+	switch n := node.(type) {
+	case *expr.Condition:
+		return r.renderCondition(n)
+	case *expr.Group:
+		return r.renderGroup(n, root)
+	case *expr.Not:
+		inner, err := r.render(n.Node, false)
+		if err != nil || inner == "" {
+			return "", err
+		}
+		return "NOT (" + inner + ")", nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unknown node type [%T]", node)
+	}
+}
+
+func (r *sqlRenderer) renderGroup(g *expr.Group, root bool) (string, error) {
+	if g.Operator != expr.AND && g.Operator != expr.OR {
+		return "", fmt.Errorf("unknown operator [%s]", g.Operator)
+	}
+
+	parts := make([]string, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		part, err := r.render(n, false)
+		if err != nil {
+			return "", err
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", nil
+	}
+
+	joined := strings.Join(parts, " "+string(g.Operator)+" ")
+	if root && g.Operator == expr.AND {
+		return joined, nil
+	}
+	return "(" + joined + ")", nil
+}
+
+func (r *sqlRenderer) renderCondition(c *expr.Condition) (string, error) {
+	f, ok := r.filter.filters[c.MatchMode]
+	if !ok {
+		return "", fmt.Errorf("match mode not registered [%s]", c.MatchMode)
+	}
+
+	if c.Value == nil {
+		return "", nil
+	}
+
+	sql, args, err := filter.RenderValue(r.ctx, f, r.filter.columns.Column(c.Column), c.Value)
+	if err != nil {
+		return "", &ValueError{Column: c.Column, MatchMode: c.MatchMode, Err: err}
+	}
+
+	bound, err := filter.Bind(sql, args, len(r.vals)+1, r.filter.placeholder)
+	if err != nil {
+		return "", &ValueError{Column: c.Column, MatchMode: c.MatchMode, Err: err}
+	}
+	if !r.markers {
+		sql = bound
+	}
+	r.vals = append(r.vals, args...)
+	for range args {
+		r.redacted = append(r.redacted, r.sensitive[c.Column])
+	}
+	return sql, nil
+}
@@ -0,0 +1,385 @@
+package prime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/spec"
+	"testing"
+)
+
+func newTreeFilter() *Filter {
+	return NewWithFilters(placeholder.Numbered("$"), map[filter.MatchMode]filter.Filter{
+		filter.EQUALS:       filters.ValueFilter("="),
+		filter.GREATER_THAN: filters.ValueFilter(">"),
+		filter.IN:           filters.InFilter(0),
+		filter.BETWEEN:      filters.BetweenFilter(0),
+		filter.STARTS_WITH:  filters.NewPatternMatchFilter("LIKE", filters.POST),
+	})
+}
+
+func TestSqlExpr(t *testing.T) {
+	tree := expr.And(
+		expr.Or(expr.Cond("status", filter.EQUALS, "open"), expr.Cond("priority", filter.GREATER_THAN, 3)),
+		expr.Cond("owner", filter.IN, []any{"amy", "anna"}),
+		&expr.Not{Node: expr.Cond("activity", filter.BETWEEN, []any{68, 100})},
+	)
+
+	vals, condition, err := newTreeFilter().SqlExpr(tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedCondition := "((status = $1) or (priority > $2)) and (owner IN ($3,$4)) and NOT ((activity BETWEEN $5 AND $6))"
+	if condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+	}
+
+	expectedValues := []any{"open", 3, "amy", "anna", 68, 100}
+	if len(vals) != len(expectedValues) {
+		t.Fatalf("expected %d values, got %d", len(expectedValues), len(vals))
+	}
+	for i, v := range expectedValues {
+		if vals[i] != v {
+			t.Errorf("expected value %v at index %d, got %v", v, i, vals[i])
+		}
+	}
+}
+
+func TestSqlExprRootOr(t *testing.T) {
+	tree := expr.Or(expr.Cond("name", filter.STARTS_WITH, "Ja"), expr.Cond("name", filter.EQUALS, "Bob"))
+
+	vals, condition, err := newTreeFilter().SqlExpr(tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedCondition := "((name LIKE $1) or (name = $2))"
+	if condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+	}
+
+	if vals[0] != "Ja%" {
+		t.Errorf("expected enriched value Ja%%, got %v", vals[0])
+	}
+}
+
+func TestSqlExprSkipsEmpty(t *testing.T) {
+	tree := expr.And(
+		expr.Or(expr.Cond("status", filter.EQUALS, nil)),
+		&expr.Not{Node: expr.And()},
+		expr.Cond("priority", filter.GREATER_THAN, 3),
+	)
+
+	vals, condition, err := newTreeFilter().SqlExpr(tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if condition != "(priority > $1)" {
+		t.Errorf("expected condition (priority > $1), got %s", condition)
+	}
+
+	if len(vals) != 1 {
+		t.Errorf("expected 1 value, got %d", len(vals))
+	}
+}
+
+func TestSqlExprErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		tree expr.Node
+	}{
+		{"unregistered match mode", expr.Cond("name", filter.DATE_IS, "2024-01-01")},
+		{"unknown operator", &expr.Group{Operator: "xor", Nodes: []expr.Node{expr.Cond("name", filter.EQUALS, "a")}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := newTreeFilter().SqlExpr(test.tree); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestSqlExprValueError(t *testing.T) {
+	_, _, err := newTreeFilter().SqlExpr(expr.Cond("activity", filter.BETWEEN, []any{68}))
+
+	var valueErr *ValueError
+	if !errors.As(err, &valueErr) {
+		t.Fatalf("expected a *ValueError, got %v", err)
+	}
+
+	if valueErr.Column != "activity" || valueErr.MatchMode != filter.BETWEEN || valueErr.Err == nil {
+		t.Errorf("unexpected error %+v", valueErr)
+	}
+}
+
+func TestSpecsExpr(t *testing.T) {
+	specs := Specs{
+		"name": {
+			{Value: "James", MatchMode: filter.STARTS_WITH, Operator: "OR"},
+			{Value: "Bob", MatchMode: filter.EQUALS, Operator: "OR"},
+		},
+		"activity": {
+			{Value: []any{68, 100}, MatchMode: filter.BETWEEN},
+		},
+	}
+
+	vals, condition, err := newTreeFilter().SqlExpr(specs.Expr())
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedCondition := "((activity BETWEEN $1 AND $2)) and ((name LIKE $3) or (name = $4))"
+	if condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+	}
+
+	if len(vals) != 4 {
+		t.Errorf("expected 4 values, got %d", len(vals))
+	}
+}
+
+func TestValidateExpr(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterColumnValidator(column.AllowedValidator{"status"})
+
+	if err := pf.ValidateExpr(expr.Or(expr.Cond("status", filter.EQUALS, "open"))); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+
+	err := pf.ValidateExpr(expr.And(&expr.Not{Node: expr.Cond("owner", filter.EQUALS, "amy")}))
+	if err == nil {
+		t.Fatal("expected an error due to invalid column, got nil")
+	}
+
+	expectedErrMsg := "AllowedValidator: column [owner] is not allowed"
+	if err.Error() != expectedErrMsg {
+		t.Errorf("expected error message '%s', got '%s'", expectedErrMsg, err.Error())
+	}
+}
+
+func TestSqlNumbersMultiValuedConstraints(t *testing.T) {
+	specs := Specs{
+		"activity": {
+			{Value: []any{68, 100}, MatchMode: filter.BETWEEN, Operator: "or"},
+			{Value: 3, MatchMode: filter.EQUALS, Operator: "or"},
+		},
+	}
+
+	vals, condition, err := newTreeFilter().Sql(specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedCondition := "((activity BETWEEN $1 AND $2) or (activity = $3))"
+	if condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+	}
+
+	if len(vals) != 3 {
+		t.Errorf("expected 3 values, got %d", len(vals))
+	}
+}
+
+func TestSqlRejectsUnknownOperator(t *testing.T) {
+	specs := Specs{
+		"name": {{Value: "a", MatchMode: filter.EQUALS, Operator: "or 1=1 --"}},
+	}
+
+	if _, _, err := newTreeFilter().Sql(specs); err == nil {
+		t.Error("expected an error for an unknown operator, got nil")
+	}
+}
+
+func TestRegisterRewriter(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterRewriter(func(n expr.Node) (expr.Node, error) {
+		if c, ok := n.(*expr.Condition); ok {
+			c.Column = "c." + c.Column
+		}
+		return n, nil
+	})
+
+	specs := Specs{"name": {{Value: "Ja", MatchMode: filter.STARTS_WITH}}}
+
+	_, condition, err := pf.Sql(specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if condition != "((c.name LIKE $1))" {
+		t.Errorf("expected condition ((c.name LIKE $1)), got %s", condition)
+	}
+
+	if specs["name"][0].Value != "Ja" {
+		t.Errorf("expected specs to be left untouched, got %v", specs["name"][0].Value)
+	}
+}
+
+func TestRegisterColumns(t *testing.T) {
+	type customer struct {
+		Name    string `prime:"name,col=c.name"`
+		Country string `prime:"country.name,col=co.name"`
+	}
+
+	columns, err := column.FromStruct[customer]()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	pf := newTreeFilter()
+	pf.RegisterColumns(columns)
+
+	specs := Specs{
+		"name":         {{Value: "Ja", MatchMode: filter.STARTS_WITH}},
+		"country.name": {{Value: "Egypt", MatchMode: filter.EQUALS}},
+	}
+
+	if err = pf.ValidateColumns(specs); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	_, condition, err := pf.Sql(specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedCondition := "((co.name = $1)) and ((c.name LIKE $2))"
+	if condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+	}
+
+	err = pf.ValidateColumns(Specs{"c.name": {{Value: "Ja", MatchMode: filter.STARTS_WITH}}})
+	if err == nil || err.Error() != "ConfigValidator: column [c.name] is not allowed" {
+		t.Errorf("expected ConfigValidator error, got %v", err)
+	}
+}
+
+func TestRegisterColumnModes(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterColumnModes("id", filter.EQUALS, filter.IN)
+
+	_, _, err := pf.Sql(Specs{"id": {{Value: []any{1, 2}, MatchMode: filter.IN}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	_, _, err = pf.Sql(Specs{"id": {{Value: "1", MatchMode: filter.STARTS_WITH}}})
+
+	var modeErr *MatchModeError
+	if !errors.As(err, &modeErr) {
+		t.Fatalf("expected a *MatchModeError, got %v", err)
+	}
+
+	if modeErr.Column != "id" || modeErr.MatchMode != filter.STARTS_WITH || len(modeErr.Allowed) != 2 {
+		t.Errorf("unexpected error %+v", modeErr)
+	}
+
+	expectedErrMsg := "match mode [startsWith] is not allowed on column [id]"
+	if err.Error() != expectedErrMsg {
+		t.Errorf("expected error message '%s', got '%s'", expectedErrMsg, err.Error())
+	}
+
+	_, _, err = pf.Sql(Specs{"id": {{Value: nil, MatchMode: filter.STARTS_WITH}}})
+	if err != nil {
+		t.Errorf("expected a condition without value to be ignored, got %v", err)
+	}
+}
+
+func TestColumnModesFromConfig(t *testing.T) {
+	type customer struct {
+		Name string `prime:"name,modes=startsWith"`
+	}
+
+	columns, err := column.FromStruct[customer]()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	pf := newTreeFilter()
+	pf.RegisterColumns(columns)
+
+	_, _, err = pf.SqlExpr(expr.Or(expr.Cond("name", filter.EQUALS, "James")))
+
+	var modeErr *MatchModeError
+	if !errors.As(err, &modeErr) {
+		t.Fatalf("expected a *MatchModeError, got %v", err)
+	}
+}
+
+func TestRegisterSpecValidator(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterSpecValidator(spec.ScopedValidator{
+		Validator: spec.MaxItemsValidator(2),
+		Columns:   []string{"status"},
+		Modes:     []filter.MatchMode{filter.IN},
+	})
+	pf.RegisterSpecValidator(spec.ScopedValidator{
+		Validator: spec.MinLengthValidator(3),
+		Modes:     []filter.MatchMode{filter.STARTS_WITH},
+	})
+
+	valid := Specs{
+		"status": {{Value: []any{"open", "closed"}, MatchMode: filter.IN}},
+		"name":   {{Value: "Jam", MatchMode: filter.STARTS_WITH}, {Value: nil, MatchMode: filter.STARTS_WITH}},
+	}
+	if err := pf.ValidateColumns(valid); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	err := pf.ValidateColumns(Specs{"status": {{Value: []any{"open", "closed", "pending"}, MatchMode: filter.IN}}})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+
+	if validationErr.Validator != "MaxItemsValidator" || validationErr.Column != "status" || validationErr.MatchMode != filter.IN {
+		t.Errorf("unexpected error %+v", validationErr)
+	}
+
+	expectedErrMsg := "MaxItemsValidator: column [status] has 3 values, more than the maximum of 2"
+	if err.Error() != expectedErrMsg {
+		t.Errorf("expected error message '%s', got '%s'", expectedErrMsg, err.Error())
+	}
+
+	if err = pf.ValidateColumns(Specs{"name": {{Value: "Ja", MatchMode: filter.STARTS_WITH}}}); err == nil {
+		t.Error("expected an error for a too short value, got nil")
+	}
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterRenderer("overlaps", filter.RenderFunc(func(_ context.Context, column string, values []any) (string, []any, error) {
+		if len(values) != 2 {
+			return "", nil, fmt.Errorf("overlaps requires 2 values, got %d", len(values))
+		}
+		return fmt.Sprintf("(%s && tstzrange(?, ?))", column), values, nil
+	}))
+
+	tree := expr.And(expr.Cond("status", filter.EQUALS, "open"), expr.Cond("period", "overlaps", []any{"2024-01-01", "2024-02-01"}))
+	vals, condition, err := pf.SqlExpr(tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedCondition := "(status = $1) and (period && tstzrange($2, $3))"
+	if condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+	}
+	if len(vals) != 3 {
+		t.Errorf("expected 3 values, got %v", vals)
+	}
+
+	if _, _, err = pf.SqlExpr(expr.Cond("period", "overlaps", "2024-01-01")); err == nil {
+		t.Error("expected the error of the renderer, got nil")
+	}
+}
@@ -0,0 +1,36 @@
+package prime
+
+import (
+	"context"
+	"github.com/AdamShannag/goprime/filter"
+	"time"
+)
+
+// RequestOption sets a request-scoped setting read by context-aware filters.
+type RequestOption func(*filter.Request)
+
+// WithLocation sets the time zone of the caller, e.g. used by filters.LocalDateFilter.
+func WithLocation(loc *time.Location) RequestOption {
+	return func(r *filter.Request) {
+		r.Location = loc
+	}
+}
+
+// WithLocale sets the locale of the caller.
+func WithLocale(locale string) RequestOption {
+	return func(r *filter.Request) {
+		r.Locale = locale
+	}
+}
+
+// withRequest applies the options to the request settings carried by ctx.
+func withRequest(ctx context.Context, opts []RequestOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	request := filter.RequestFromContext(ctx)
+	for _, opt := range opts {
+		opt(&request)
+	}
+	return filter.WithRequest(ctx, request)
+}
@@ -0,0 +1,98 @@
+package prime
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/placeholder"
+	"testing"
+	"time"
+)
+
+type roleKey struct{}
+
+// ownedFilter restricts "mine" to the rows of the caller unless the caller is an admin.
+type ownedFilter struct{}
+
+func (ownedFilter) Apply(column string, _, currentIndex int, placeholder placeholder.Placeholder) string {
+	return fmt.Sprintf("(%s = %s)", column, placeholder.Get(currentIndex))
+}
+
+func (ownedFilter) EnrichValue(*any) error { return nil }
+
+func (f ownedFilter) ApplyContext(ctx context.Context, column string, total, currentIndex int, placeholder placeholder.Placeholder) string {
+	if ctx.Value(roleKey{}) == "admin" {
+		return fmt.Sprintf("(%s = %s OR TRUE)", column, placeholder.Get(currentIndex))
+	}
+	return f.Apply(column, total, currentIndex, placeholder)
+}
+
+func (ownedFilter) EnrichValueContext(ctx context.Context, value *any) error {
+	if *value == "me" {
+		*value = ctx.Value(roleKey{})
+	}
+	return nil
+}
+
+func TestSqlContextWithContextFilter(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterFilter("owned", ownedFilter{})
+
+	specs := Specs{"owner": {{Value: "me", MatchMode: "owned"}}}
+
+	tests := []struct {
+		role              string
+		expectedCondition string
+	}{
+		{"admin", "((owner = $1 OR TRUE))"},
+		{"clerk", "((owner = $1))"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.role, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), roleKey{}, test.role)
+			vals, condition, err := pf.SqlContext(ctx, specs)
+			if err != nil {
+				t.Fatalf("expected nil error, got %v", err)
+			}
+			if condition != test.expectedCondition {
+				t.Errorf("expected condition %s, got %s", test.expectedCondition, condition)
+			}
+			if len(vals) != 1 || vals[0] != test.role {
+				t.Errorf("expected value %s, got %v", test.role, vals)
+			}
+		})
+	}
+}
+
+func TestSqlContextWithLocation(t *testing.T) {
+	pf := New(WithPlaceholder(placeholder.Numbered("$")))
+	pf.RegisterFilter(filter.DATE_IS, filters.LocalDateFilter(filter.DATE_IS))
+
+	specs := Specs{"date": {{Value: "2024-08-12T21:00:00.000Z", MatchMode: filter.DATE_IS}}}
+	tokyo := time.FixedZone("Tokyo", 9*60*60)
+
+	vals, condition, err := pf.SqlContext(context.Background(), specs, WithLocation(tokyo), WithLocale("ja-JP"))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedCondition := "((date >= $1 AND date < $2))"
+	if condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+	}
+
+	expectedStart := time.Date(2024, 8, 13, 0, 0, 0, 0, tokyo)
+	if len(vals) != 2 || !vals[0].(time.Time).Equal(expectedStart) || !vals[1].(time.Time).Equal(expectedStart.AddDate(0, 0, 1)) {
+		t.Errorf("expected the bounds of %v, got %v", expectedStart, vals)
+	}
+
+	vals, _, err = pf.Sql(specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if expected := time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC); !vals[0].(time.Time).Equal(expected) {
+		t.Errorf("expected %v without a location, got %v", expected, vals[0])
+	}
+}
@@ -0,0 +1,95 @@
+package prime
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/expr"
+)
+
+// Predicate returns a condition every generated query must satisfy, e.g. scoping the query to the
+// tenant of the caller. A nil node adds no condition; an error aborts the query, so a predicate
+// should fail when the information it needs is missing from the context.
+type Predicate func(ctx context.Context) (expr.Node, error)
+
+type predicatesKey struct{}
+
+// WithPredicates returns a copy of ctx carrying mandatory conditions for a single request,
+// in addition to the ones already carried by ctx.
+// Parameters:
+//
+//	ctx: The context of the request.
+//	nodes: The conditions every query generated with the returned context must satisfy.
+//
+// Returns:
+//
+//	The derived context.
+func WithPredicates(ctx context.Context, nodes ...expr.Node) context.Context {
+	existing, _ := ctx.Value(predicatesKey{}).([]expr.Node)
+	return context.WithValue(ctx, predicatesKey{}, append(existing[:len(existing):len(existing)], nodes...))
+}
+
+// RegisterPredicate adds a mandatory predicate that is ANDed into every generated query.
+// Parameters:
+//
+//	predicate: The function returning the condition for the context of a query.
+func (f *Filter) RegisterPredicate(predicate Predicate) {
+	f.predicates = append(f.predicates, predicate)
+}
+
+// Predicates returns the mandatory conditions for a context: the conditions of the registered
+// predicates followed by the ones added with WithPredicates.
+// Parameters:
+//
+//	ctx: The context of the query.
+//
+// Returns:
+//
+//	The mandatory conditions, or an error if a predicate fails or a condition has no value.
+func (f *Filter) Predicates(ctx context.Context) ([]expr.Node, error) {
+	var nodes []expr.Node
+	for _, predicate := range f.predicates {
+		node, err := predicate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	if request, ok := ctx.Value(predicatesKey{}).([]expr.Node); ok {
+		nodes = append(nodes, request...)
+	}
+
+	for _, node := range nodes {
+		for _, c := range expr.Conditions(node) {
+			// a condition with a nil value is skipped when rendered, which would silently drop the restriction
+			if c.Value == nil {
+				return nil, fmt.Errorf("mandatory predicate on column [%s] has no value", c.Column)
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// Scope ANDs the mandatory conditions for a context around a filter tree.
+// The tree becomes a single child of the returned group, so none of its operators or negations
+// can reach the mandatory conditions. Scope is applied after validation and rewriting,
+// so the mandatory conditions are neither validated nor rewritten.
+// Parameters:
+//
+//	ctx: The context of the query.
+//	node: The root node of the filter tree sent by the client.
+//
+// Returns:
+//
+//	The scoped tree, or node itself if there are no mandatory conditions.
+func (f *Filter) Scope(ctx context.Context, node expr.Node) (expr.Node, error) {
+	nodes, err := f.Predicates(ctx)
+	if err != nil || len(nodes) == 0 {
+		return node, err
+	}
+	if node != nil {
+		nodes = append(nodes, node)
+	}
+	return expr.And(nodes...), nil
+}
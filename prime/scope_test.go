@@ -0,0 +1,180 @@
+package prime
+
+import (
+	"context"
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func tenantPredicate(ctx context.Context) (expr.Node, error) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	if !ok {
+		return nil, errors.New("no tenant in context")
+	}
+	return expr.Cond("tenant_id", filter.EQUALS, tenant), nil
+}
+
+func TestSqlContext(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterPredicate(tenantPredicate)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	ctx = WithPredicates(ctx, expr.Cond("region", filter.IN, []any{"eu", "us"}))
+
+	specs := Specs{
+		"name": {
+			{Value: "Ja", MatchMode: filter.STARTS_WITH, Operator: "or"},
+			{Value: "Bob", MatchMode: filter.EQUALS, Operator: "or"},
+		},
+	}
+
+	vals, condition, err := pf.SqlContext(ctx, specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedCondition := "(tenant_id = $1) and (region IN ($2,$3)) and (((name LIKE $4) or (name = $5)))"
+	if condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+	}
+
+	expectedValues := []any{"acme", "eu", "us", "Ja%", "Bob"}
+	if len(vals) != len(expectedValues) {
+		t.Fatalf("expected %d values, got %d", len(expectedValues), len(vals))
+	}
+	for i, v := range expectedValues {
+		if vals[i] != v {
+			t.Errorf("expected value %v at index %d, got %v", v, i, vals[i])
+		}
+	}
+}
+
+func TestSqlContextCannotBeNegated(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterPredicate(tenantPredicate)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	tree := &expr.Not{Node: expr.Or(expr.Cond("tenant_id", filter.EQUALS, "other"), expr.Cond("id", filter.GREATER_THAN, 0))}
+
+	_, condition, err := pf.SqlExprContext(ctx, tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedCondition := "(tenant_id = $1) and NOT (((tenant_id = $2) or (id > $3)))"
+	if condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+	}
+}
+
+func TestSqlContextWithoutClientFilters(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterPredicate(tenantPredicate)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	vals, condition, err := pf.SqlContext(ctx, Specs{"name": {{Value: nil, MatchMode: filter.EQUALS}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if condition != "(tenant_id = $1)" || len(vals) != 1 {
+		t.Errorf("expected only the tenant condition, got %s %v", condition, vals)
+	}
+}
+
+func TestSqlContextFailsClosed(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterPredicate(tenantPredicate)
+
+	specs := Specs{"name": {{Value: "Bob", MatchMode: filter.EQUALS}}}
+
+	if _, _, err := pf.Sql(specs); err == nil {
+		t.Error("expected an error without a tenant in the context, got nil")
+	}
+
+	ctx := WithPredicates(context.WithValue(context.Background(), tenantKey{}, "acme"), expr.Cond("region", filter.EQUALS, nil))
+	if _, _, err := pf.SqlContext(ctx, specs); err == nil {
+		t.Error("expected an error for a mandatory condition without a value, got nil")
+	}
+}
+
+func TestWithPredicatesAppends(t *testing.T) {
+	parent := WithPredicates(context.Background(), expr.Cond("a", filter.EQUALS, 1))
+	first := WithPredicates(parent, expr.Cond("b", filter.EQUALS, 2))
+	second := WithPredicates(parent, expr.Cond("c", filter.EQUALS, 3))
+
+	pf := newTreeFilter()
+	_, condition, err := pf.SqlExprContext(first, nil)
+	if err != nil || condition != "(a = $1) and (b = $2)" {
+		t.Errorf("expected (a = $1) and (b = $2), got %s (%v)", condition, err)
+	}
+
+	_, condition, err = pf.SqlExprContext(second, nil)
+	if err != nil || condition != "(a = $1) and (c = $2)" {
+		t.Errorf("expected (a = $1) and (c = $2), got %s (%v)", condition, err)
+	}
+}
+
+func TestSqlContextValidatesColumns(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterPredicate(tenantPredicate)
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	specs := Specs{"a))) OR 1=1 OR (((a": {{Value: "x", MatchMode: filter.EQUALS}}}
+	_, condition, err := pf.SqlContext(ctx, specs)
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a *ValidationError, got condition %s, %v", condition, err)
+	}
+	if validationErr.Column != "a))) OR 1=1 OR (((a" {
+		t.Errorf("expected the injected column, got %s", validationErr.Column)
+	}
+
+	pf.RegisterColumns(column.Configs{"name": {Field: "name", Column: "c.name"}})
+	if _, _, err := pf.SqlContext(ctx, Specs{"other": {{Value: "x", MatchMode: filter.EQUALS}}}); !errors.As(err, &validationErr) {
+		t.Errorf("expected a *ValidationError for an unregistered field, got %v", err)
+	}
+}
+
+func TestWithRewriters(t *testing.T) {
+	pf := newTreeFilter()
+	ctx := WithRewriters(context.Background(), func(n expr.Node) (expr.Node, error) {
+		if c, ok := n.(*expr.Condition); ok {
+			c.Column = `"customers"."` + c.Column + `"`
+		}
+		return n, nil
+	})
+
+	_, condition, err := pf.SqlContext(ctx, Specs{"name": {{Value: "Ja", MatchMode: filter.EQUALS}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := `(("customers"."name" = $1))`
+	if condition != expected {
+		t.Errorf("expected condition %s, got %s", expected, condition)
+	}
+}
+
+func TestWithMarkers(t *testing.T) {
+	pf := newTreeFilter()
+	pf.RegisterFilter(filter.CONTAINS, filters.ValueFilter("?"))
+	specs := Specs{"tags": {{Value: "vip", MatchMode: filter.CONTAINS}}, "name": {{Value: "Ja", MatchMode: filter.EQUALS}}}
+
+	vals, condition, err := pf.SqlContext(WithMarkers(context.Background()), specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := "((name = ?)) and ((tags ?? ?))"
+	if condition != expected || len(vals) != 2 {
+		t.Errorf("expected condition %s, got %s %v", expected, condition, vals)
+	}
+}
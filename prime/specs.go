@@ -1,20 +1,41 @@
 package prime
 
 import (
+	"github.com/AdamShannag/goprime/expr"
 	"github.com/AdamShannag/goprime/filter"
-	"iter"
+	"maps"
+	"slices"
+	"strings"
 )
 
 type Specs map[string][]filter.Spec
 
-func (s Specs) iter() iter.Seq2[string, filter.Spec] {
-	return func(yield func(string, filter.Spec) bool) {
-		for k, v := range s {
-			for _, f := range v {
-				if !yield(k, f) {
-					return
-				}
-			}
+// Expr converts the flat Specs into a filter tree.
+// Every column becomes a Group of its constraints, combined with the operator of its first constraint
+// (PrimeNG uses a single operator per column), and the columns are combined with AND in a stable order.
+//
+// Returns:
+//
+//	The root Group of the filter tree.
+func (s Specs) Expr() *expr.Group {
+	root := expr.And()
+	for _, col := range slices.Sorted(maps.Keys(s)) {
+		specs := s[col]
+		if len(specs) == 0 {
+			continue
 		}
+		group := &expr.Group{Operator: operator(specs[0].Operator)}
+		for _, spec := range specs {
+			group.Nodes = append(group.Nodes, expr.Cond(col, spec.MatchMode, spec.Value))
+		}
+		root.Nodes = append(root.Nodes, group)
+	}
+	return root
+}
+
+func operator(op string) expr.Operator {
+	if op == "" {
+		return expr.AND
 	}
+	return expr.Operator(strings.ToLower(op))
 }
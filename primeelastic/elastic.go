@@ -0,0 +1,261 @@
+package primeelastic
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/prime"
+)
+
+// Renderer turns filter specifications into Elasticsearch/OpenSearch "bool" queries.
+// It shares its filter contract with a prime.Filter: only the match modes registered on the
+// prime.Filter are accepted, and its column validators, allowed match modes, limits and rewriters are
+// applied before rendering.
+// The returned queries are JSON-serialisable maps that can be sent as the "query" of a search request.
+type Renderer struct {
+	filter       prime.ReadOnlyFilter       // Filter providing the match modes, column validators and rewriters
+	queries      map[filter.MatchMode]Query // Map of match modes to their corresponding queries
+	globalFields []string                   // Fields searched by the global filter
+}
+
+// New creates a new Renderer for the given prime.Filter, initialized with DefaultQueries.
+// Parameters:
+//
+//	pf: The prime.Filter whose match modes, column validators and rewriters are used.
+//	globalFields: The fields searched by the global filter; when empty, the index default fields are searched.
+//
+// Returns:
+//
+//	A pointer to a newly created Renderer instance.
+func New(pf prime.ReadOnlyFilter, globalFields ...string) *Renderer {
+	return &Renderer{filter: pf, queries: DefaultQueries(), globalFields: globalFields}
+}
+
+// RegisterQuery adds or replaces the query for a specific match mode.
+// Parameters:
+//
+//	matchMode: The match mode for which to register the query.
+//	query: The query to be registered for the specified match mode.
+func (r *Renderer) RegisterQuery(matchMode filter.MatchMode, query Query) {
+	r.queries[matchMode] = query
+}
+
+// Query generates a "bool" query based on the provided Specs.
+// Parameters:
+//
+//	specs: The Specs object that provides the specifications for generating the query.
+//
+// Returns:
+//
+//	A JSON-serialisable "bool" query, or an error if a column is invalid, a match mode is not
+//	registered or a value is not supported by its query.
+func (r *Renderer) Query(specs prime.Specs) (map[string]any, error) {
+	return r.QueryExprContext(context.Background(), specs.Expr())
+}
+
+// QueryContext generates a "bool" query based on the provided Specs, ANDed with the
+// mandatory predicates registered on the prime.Filter and carried by the context.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the predicates of the prime.Filter.
+//	specs: The Specs object that provides the specifications for generating the query.
+//
+// Returns:
+//
+//	A JSON-serialisable "bool" query, or an error as returned by Query or by a failing predicate.
+func (r *Renderer) QueryContext(ctx context.Context, specs prime.Specs) (map[string]any, error) {
+	return r.QueryExprContext(ctx, specs.Expr())
+}
+
+// QueryEvent generates a "bool" query based on the filters of a lazy load event.
+// The global filter of the event is added as a "multi_match" query on the global fields.
+// Parameters:
+//
+//	event: The lazy load event of the table.
+//
+// Returns:
+//
+//	A JSON-serialisable "bool" query.
+func (r *Renderer) QueryEvent(event prime.LazyLoadEvent) (map[string]any, error) {
+	return r.QueryEventContext(context.Background(), event)
+}
+
+// QueryEventContext generates a "bool" query based on a lazy load event like QueryEvent,
+// ANDed with the mandatory predicates for the context.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the predicates of the prime.Filter.
+//	event: The lazy load event of the table.
+//
+// Returns:
+//
+//	A JSON-serialisable "bool" query.
+func (r *Renderer) QueryEventContext(ctx context.Context, event prime.LazyLoadEvent) (map[string]any, error) {
+	query, err := r.QueryContext(ctx, event.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if global := event.Global(); global != "" {
+		multiMatch := map[string]any{"query": global}
+		if len(r.globalFields) > 0 {
+			multiMatch["fields"] = r.globalFields
+		}
+		query["bool"].(map[string]any)["must"] = []any{map[string]any{"multi_match": multiMatch}}
+	}
+	return query, nil
+}
+
+// QueryExpr generates a "bool" query from a filter tree.
+// AND groups become "filter" clauses, OR groups become "should" clauses and negations
+// become "must_not" clauses.
+// Parameters:
+//
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	A JSON-serialisable "bool" query; an empty "bool" query matches every document.
+func (r *Renderer) QueryExpr(node expr.Node) (map[string]any, error) {
+	return r.QueryExprContext(context.Background(), node)
+}
+
+// QueryExprContext generates a "bool" query from a filter tree, ANDed with the mandatory predicates
+// for the context. The filter tree is validated and rewritten before the predicates are added,
+// so the client can neither negate nor bypass them.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the predicates of the prime.Filter.
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	A JSON-serialisable "bool" query, or an error as returned by QueryExpr or by a failing predicate.
+func (r *Renderer) QueryExprContext(ctx context.Context, node expr.Node) (map[string]any, error) {
+	if err := r.filter.ValidateExpr(node); err != nil {
+		return nil, err
+	}
+
+	if err := r.filter.ValidateModes(node); err != nil {
+		return nil, err
+	}
+
+	if err := r.filter.ValidateLimits(node); err != nil {
+		return nil, err
+	}
+
+	node, err := r.filter.RewriteContext(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err = r.filter.Scope(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	clause, err := r.render(node)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case clause == nil:
+		return map[string]any{"bool": map[string]any{}}, nil
+	case clause["bool"] != nil:
+		return clause, nil
+	default:
+		return map[string]any{"bool": map[string]any{"filter": []any{clause}}}, nil
+	}
+}
+
+func (r *Renderer) render(node expr.Node) (map[string]any, error) {
+	switch n := node.(type) {
+	case *expr.Condition:
+		return r.renderCondition(n)
+	case *expr.Group:
+		return r.renderGroup(n)
+	case *expr.Not:
+		inner, err := r.render(n.Node)
+		if err != nil || inner == nil {
+			return nil, err
+		}
+		return mustNot(inner), nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown node type [%T]", node)
+	}
+}
+
+func (r *Renderer) renderGroup(g *expr.Group) (map[string]any, error) {
+	if g.Operator != expr.AND && g.Operator != expr.OR {
+		return nil, fmt.Errorf("unknown operator [%s]", g.Operator)
+	}
+
+	var clauses, negated []any
+	for _, n := range g.Nodes {
+		clause, err := r.render(n)
+		if err != nil {
+			return nil, err
+		}
+		if clause == nil {
+			continue
+		}
+		if inner, ok := negation(clause); ok && g.Operator == expr.AND {
+			negated = append(negated, inner...)
+			continue
+		}
+		clauses = append(clauses, clause)
+	}
+
+	switch {
+	case len(clauses)+len(negated) == 0:
+		return nil, nil
+	case len(clauses) == 1 && len(negated) == 0:
+		return clauses[0].(map[string]any), nil
+	case g.Operator == expr.OR:
+		return map[string]any{"bool": map[string]any{"should": clauses, "minimum_should_match": 1}}, nil
+	}
+
+	b := make(map[string]any)
+	if len(clauses) > 0 {
+		b["filter"] = clauses
+	}
+	if len(negated) > 0 {
+		b["must_not"] = negated
+	}
+	return map[string]any{"bool": b}, nil
+}
+
+func (r *Renderer) renderCondition(c *expr.Condition) (map[string]any, error) {
+	if !r.filter.HasFilter(c.MatchMode) {
+		return nil, fmt.Errorf("match mode not registered [%s]", c.MatchMode)
+	}
+
+	query, ok := r.queries[c.MatchMode]
+	if !ok {
+		return nil, fmt.Errorf("no elastic query for match mode [%s]", c.MatchMode)
+	}
+
+	if c.Value == nil {
+		return nil, nil
+	}
+
+	clause, err := query.Query(c.Column, c.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.Column, err)
+	}
+	return clause, nil
+}
+
+// negation returns the clauses of a "bool" query made only of "must_not" clauses.
+func negation(clause map[string]any) ([]any, bool) {
+	b, ok := clause["bool"].(map[string]any)
+	if !ok || len(b) != 1 {
+		return nil, false
+	}
+	inner, ok := b["must_not"].([]any)
+	return inner, ok
+}
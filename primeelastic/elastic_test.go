@@ -0,0 +1,202 @@
+package primeelastic
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/prime"
+	"testing"
+)
+
+func newRenderer() *Renderer {
+	pf := prime.New(prime.WithPlaceholder(placeholder.Numbered("$")))
+	pf.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+	pf.RegisterFilter(filter.CONTAINS, filters.NewPatternMatchFilter("LIKE", filters.AROUND))
+	pf.RegisterFilter(filter.NOT_EQUALS, filters.ValueFilter("<>"))
+	pf.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+	pf.RegisterFilter(filter.GREATER_THAN, filters.ValueFilter(">"))
+	pf.RegisterFilter(filter.IN, filters.InFilter(0))
+	pf.RegisterFilter(filter.BETWEEN, filters.BetweenFilter(0))
+	pf.RegisterFilter(filter.DATE_AFTER, filters.DateAfterFilter(0))
+	pf.RegisterColumnValidator(column.AllowedValidator{"name", "representative", "date", "activity", "status", "priority"})
+	return New(pf, "name", "representative")
+}
+
+func assertJSON(t *testing.T, expected string, actual map[string]any) {
+	t.Helper()
+	var want any
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("invalid expected JSON: %v", err)
+	}
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, err := json.Marshal(actual)
+	if err != nil {
+		t.Fatalf("expected the query to be JSON-serialisable, got %v", err)
+	}
+	if string(wantJSON) != string(gotJSON) {
+		t.Errorf("expected query %s, got %s", wantJSON, gotJSON)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	specs := prime.Specs{
+		"name": {
+			{Value: "Ja*", MatchMode: filter.CONTAINS, Operator: "and"},
+		},
+		"representative": {
+			{Value: []any{"Amy Elsner", "Anna Fali"}, MatchMode: filter.IN, Operator: "and"},
+		},
+		"activity": {
+			{Value: []any{68, 100}, MatchMode: filter.BETWEEN, Operator: "and"},
+		},
+		"status": {
+			{Value: "closed", MatchMode: filter.NOT_EQUALS, Operator: "and"},
+		},
+	}
+
+	query, err := newRenderer().Query(specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	assertJSON(t, `{"bool": {
+		"filter": [
+			{"range": {"activity": {"gte": 68, "lte": 100}}},
+			{"wildcard": {"name": {"value": "*Ja\\**"}}},
+			{"terms": {"representative": ["Amy Elsner", "Anna Fali"]}}
+		],
+		"must_not": [
+			{"term": {"status": "closed"}}
+		]
+	}}`, query)
+}
+
+func TestQueryExpr(t *testing.T) {
+	tree := expr.Or(
+		expr.Cond("status", filter.EQUALS, "open"),
+		expr.And(expr.Cond("priority", filter.GREATER_THAN, 3), &expr.Not{Node: expr.Cond("name", filter.STARTS_WITH, "bot")}),
+	)
+
+	query, err := newRenderer().QueryExpr(tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	assertJSON(t, `{"bool": {
+		"should": [
+			{"term": {"status": "open"}},
+			{"bool": {
+				"filter": [{"range": {"priority": {"gt": 3}}}],
+				"must_not": [{"prefix": {"name": "bot"}}]
+			}}
+		],
+		"minimum_should_match": 1
+	}}`, query)
+}
+
+func TestQueryEvent(t *testing.T) {
+	event := prime.LazyLoadEvent{
+		Filters: prime.Specs{
+			"date": {{Value: "2024-08-12T21:00:00.000Z", MatchMode: filter.DATE_AFTER}},
+		},
+		GlobalFilter: "amy",
+	}
+
+	query, err := newRenderer().QueryEvent(event)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	assertJSON(t, `{"bool": {
+		"filter": [{"range": {"date": {"gt": "2024-08-12T21:00:00.000Z"}}}],
+		"must": [{"multi_match": {"query": "amy", "fields": ["name", "representative"]}}]
+	}}`, query)
+}
+
+func TestQueryEmpty(t *testing.T) {
+	query, err := newRenderer().QueryEvent(prime.LazyLoadEvent{})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	assertJSON(t, `{"bool": {}}`, query)
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs prime.Specs
+	}{
+		{"invalid column", prime.Specs{"password": {{Value: "a", MatchMode: filter.EQUALS}}}},
+		{"unregistered match mode", prime.Specs{"name": {{Value: "a", MatchMode: filter.ENDS_WITH}}}},
+		{"invalid between value", prime.Specs{"activity": {{Value: 68, MatchMode: filter.BETWEEN}}}},
+		{"invalid wildcard value", prime.Specs{"name": {{Value: 1, MatchMode: filter.CONTAINS}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := newRenderer().Query(test.specs); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestRegisterQuery(t *testing.T) {
+	r := newRenderer()
+	r.RegisterQuery(filter.EQUALS, MatchQuery(0))
+
+	query, err := r.Query(prime.Specs{"name": {{Value: "james", MatchMode: filter.EQUALS}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	assertJSON(t, `{"bool": {"filter": [{"match": {"name": "james"}}]}}`, query)
+}
+
+func TestQueryEventContext(t *testing.T) {
+	r := newRenderer()
+	r.filter.(*prime.Filter).RegisterPredicate(func(ctx context.Context) (expr.Node, error) {
+		return expr.Cond("tenant_id", filter.EQUALS, "acme"), nil
+	})
+
+	event := prime.LazyLoadEvent{
+		Filters: prime.Specs{
+			"status": {{Value: "open", MatchMode: filter.NOT_EQUALS}},
+		},
+		GlobalFilter: "amy",
+	}
+
+	query, err := r.QueryEventContext(context.Background(), event)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	assertJSON(t, `{"bool": {
+		"filter": [{"term": {"tenant_id": "acme"}}],
+		"must_not": [{"term": {"status": "open"}}],
+		"must": [{"multi_match": {"query": "amy", "fields": ["name", "representative"]}}]
+	}}`, query)
+}
+
+func TestQueryContextRewriters(t *testing.T) {
+	ctx := prime.WithRewriters(context.Background(), func(n expr.Node) (expr.Node, error) {
+		if c, ok := n.(*expr.Condition); ok && c.Column == "status" {
+			c.Column = "status.keyword"
+		}
+		return n, nil
+	})
+
+	query, err := newRenderer().QueryEventContext(ctx, prime.LazyLoadEvent{
+		Filters: prime.Specs{"status": {{Value: "open", MatchMode: filter.EQUALS}}},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	assertJSON(t, `{"bool": {"filter": [{"term": {"status.keyword": "open"}}]}}`, query)
+}
@@ -0,0 +1,148 @@
+package primeelastic
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/filter"
+	"strings"
+)
+
+// Query builds the Elasticsearch query clause for a single condition.
+type Query interface {
+	// Query creates the query clause for a field.
+	// Parameters:
+	//   field: The name of the indexed field to filter.
+	//   value: The value of the condition; a list value is passed as []any.
+	// Returns:
+	//   A JSON-serialisable query clause, or an error if the value is not supported.
+	Query(field string, value any) (map[string]any, error)
+}
+
+// QueryFunc is an adapter to allow the use of ordinary functions as a Query.
+type QueryFunc func(field string, value any) (map[string]any, error)
+
+// Query calls f(field, value).
+func (f QueryFunc) Query(field string, value any) (map[string]any, error) {
+	return f(field, value)
+}
+
+// TermQuery matches the exact value of a field with a "term" query.
+type TermQuery uint8
+
+// Query creates {"term": {field: value}}.
+func (TermQuery) Query(field string, value any) (map[string]any, error) {
+	return map[string]any{"term": map[string]any{field: value}}, nil
+}
+
+// TermsQuery matches a field against a list of values with a "terms" query.
+type TermsQuery uint8
+
+// Query creates {"terms": {field: values}}.
+func (TermsQuery) Query(field string, value any) (map[string]any, error) {
+	values, ok := value.([]any)
+	if !ok {
+		values = []any{value}
+	}
+	return map[string]any{"terms": map[string]any{field: values}}, nil
+}
+
+// MatchQuery runs a full text "match" query on a field.
+type MatchQuery uint8
+
+// Query creates {"match": {field: value}}.
+func (MatchQuery) Query(field string, value any) (map[string]any, error) {
+	return map[string]any{"match": map[string]any{field: value}}, nil
+}
+
+// PrefixQuery matches the values of a field starting with the value using a "prefix" query.
+type PrefixQuery uint8
+
+// Query creates {"prefix": {field: value}}.
+func (PrefixQuery) Query(field string, value any) (map[string]any, error) {
+	return map[string]any{"prefix": map[string]any{field: value}}, nil
+}
+
+// WildcardQuery matches the values of a field with a "wildcard" query built from a format string,
+// e.g. "*%s*" to match values containing the value. Wildcard characters in the value are escaped.
+type WildcardQuery string
+
+// Query creates {"wildcard": {field: {"value": pattern}}}.
+func (q WildcardQuery) Query(field string, value any) (map[string]any, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("wildcard value must be a string, got [%T]", value)
+	}
+	pattern := fmt.Sprintf(string(q), wildcardEscaper.Replace(str))
+	return map[string]any{"wildcard": map[string]any{field: map[string]any{"value": pattern}}}, nil
+}
+
+// RangeQuery compares a field with a single value using a "range" query operator, e.g. "gte".
+type RangeQuery string
+
+// Query creates {"range": {field: {operator: value}}}.
+func (q RangeQuery) Query(field string, value any) (map[string]any, error) {
+	return map[string]any{"range": map[string]any{field: map[string]any{string(q): value}}}, nil
+}
+
+// BetweenQuery matches a field within an inclusive range of two values with a "range" query.
+type BetweenQuery uint8
+
+// Query creates {"range": {field: {"gte": from, "lte": to}}}.
+func (BetweenQuery) Query(field string, value any) (map[string]any, error) {
+	values, ok := value.([]any)
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("between value must be a list of 2 values, got [%v]", value)
+	}
+	return map[string]any{"range": map[string]any{field: map[string]any{"gte": values[0], "lte": values[1]}}}, nil
+}
+
+// DateIsQuery matches a date field equal to the value with a "range" query,
+// which also works on date fields indexed with a time component.
+type DateIsQuery uint8
+
+// Query creates {"range": {field: {"gte": date, "lte": date}}}.
+func (DateIsQuery) Query(field string, value any) (map[string]any, error) {
+	return BetweenQuery(0).Query(field, []any{value, value})
+}
+
+// NotQuery negates the clause of the query it wraps with a bool "must_not".
+type NotQuery struct {
+	Inner Query // Query whose clause is negated
+}
+
+// Query creates {"bool": {"must_not": [clause]}}.
+func (q NotQuery) Query(field string, value any) (map[string]any, error) {
+	clause, err := q.Inner.Query(field, value)
+	if err != nil {
+		return nil, err
+	}
+	return mustNot(clause), nil
+}
+
+// DefaultQueries returns the queries for every filter.MatchMode, matching the semantics
+// of the SQL filters in the filters package.
+func DefaultQueries() map[filter.MatchMode]Query {
+	return map[filter.MatchMode]Query{
+		filter.EQUALS:              TermQuery(0),
+		filter.NOT_EQUALS:          NotQuery{TermQuery(0)},
+		filter.CONTAINS:            WildcardQuery("*%s*"),
+		filter.NOT_CONTAINS:        NotQuery{WildcardQuery("*%s*")},
+		filter.STARTS_WITH:         PrefixQuery(0),
+		filter.ENDS_WITH:           WildcardQuery("*%s"),
+		filter.LESS_THAN:           RangeQuery("lt"),
+		filter.LESS_THAN_EQUALS:    RangeQuery("lte"),
+		filter.GREATER_THAN:        RangeQuery("gt"),
+		filter.GREATER_THAN_EQUALS: RangeQuery("gte"),
+		filter.DATE_BEFORE:         RangeQuery("lt"),
+		filter.DATE_AFTER:          RangeQuery("gt"),
+		filter.DATE_IS:             DateIsQuery(0),
+		filter.DATE_IS_NOT:         NotQuery{DateIsQuery(0)},
+		filter.IN:                  TermsQuery(0),
+		filter.BETWEEN:             BetweenQuery(0),
+	}
+}
+
+var wildcardEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
+
+func mustNot(clause map[string]any) map[string]any {
+	return map[string]any{"bool": map[string]any{"must_not": []any{clause}}}
+}
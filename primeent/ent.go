@@ -0,0 +1,171 @@
+package primeent
+
+import (
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"fmt"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/prime"
+	"strings"
+	"unicode"
+)
+
+// FieldError is returned when a field of a lazy load event is not a column of the schema.
+type FieldError struct {
+	Field string // Field sent by the client
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field [%s] is not a column of the schema", e.Field)
+}
+
+// Where returns an ent predicate adding the condition of the Specs of a lazy load event to a selector.
+// The generated predicate types of ent are functions of a selector, so it converts to them:
+//
+//	client.Customer.Query().Where(predicate.Customer(primeent.Where(pf, specs, customer.ValidColumn))).All(ctx)
+//
+// See WhereExpr for how fields are mapped to columns.
+// Parameters:
+//
+//	pf: The Filter generating the condition.
+//	specs: The Specs of the event.
+//	validColumn: The ValidColumn function generated by ent for the schema; may be nil if the Filter has registered columns.
+//
+// Returns:
+//
+//	The predicate.
+func Where(pf prime.ReadOnlyFilter, specs prime.Specs, validColumn func(string) bool) func(*sql.Selector) {
+	return WhereExpr(pf, specs.Expr(), validColumn)
+}
+
+// WhereExpr returns an ent predicate adding the condition of a filter tree to a selector.
+// Fields are mapped to the columns registered on the Filter or, when the Filter has no registered columns,
+// to the columns of the schema accepted by validColumn: a field is either a column, or the camel case name
+// of a column, e.g. "countryName" for "country_name". Columns are qualified with the table of the selector.
+// The Filter validates the fields as the client sent them; a rewriter added with prime.WithRewriters only
+// qualifies them afterwards. The condition is bound to the placeholders of the selector whatever the placeholder
+// of the Filter.
+// The context of the selector is passed to the mandatory predicates and context-aware filters.
+// Errors are added to the selector and returned when the query is run; ent reports them as text only.
+// Parameters:
+//
+//	pf: The Filter generating the condition.
+//	node: The root node of the filter tree.
+//	validColumn: The ValidColumn function generated by ent for the schema; may be nil if the Filter has registered columns.
+//
+// Returns:
+//
+//	The predicate.
+func WhereExpr(pf prime.ReadOnlyFilter, node expr.Node, validColumn func(string) bool) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		ctx := s.Context()
+		if len(pf.Columns()) == 0 {
+			ctx = prime.WithRewriters(ctx, func(n expr.Node) (expr.Node, error) {
+				c, ok := n.(*expr.Condition)
+				if !ok || c.Value == nil {
+					return n, nil
+				}
+				col, err := columnOf(validColumn, c.Column)
+				c.Column = s.C(col)
+				return c, err
+			})
+		}
+
+		vals, condition, err := pf.SqlExprContext(prime.WithMarkers(ctx), node)
+		if err != nil {
+			s.AddError(err)
+			return
+		}
+		if condition == "" {
+			return
+		}
+
+		s.Where(sql.P(func(b *sql.Builder) {
+			b.Wrap(func(b *sql.Builder) {
+				var ph placeholder.Placeholder = placeholder.UnNumbered("?")
+				if b.Dialect() == dialect.Postgres {
+					ph = placeholder.Numbered("$")
+				}
+				bound, err := filter.Bind(condition, vals, b.Total()+1, ph)
+				if err != nil {
+					b.AddError(err)
+					return
+				}
+				b.Join(sql.Expr(bound, vals...))
+			})
+		}))
+	}
+}
+
+// Order returns an ent order option sorting a selector by a sort order.
+// Fields are checked with Filter.OrderBy, and mapped to columns as in WhereExpr:
+//
+//	client.Customer.Query().Order(primeent.Order(pf, event.Sorts(), customer.ValidColumn)).All(ctx)
+//
+// Parameters:
+//
+//	pf: The Filter validating the sort order.
+//	sorts: The sort order, e.g. from LazyLoadEvent.Sorts.
+//	validColumn: The ValidColumn function generated by ent for the schema; may be nil if the Filter has registered columns.
+//
+// Returns:
+//
+//	The order option, adding a *prime.SortError or *prime.ValidationError to the selector on failure.
+func Order(pf prime.ReadOnlyFilter, sorts []prime.SortMeta, validColumn func(string) bool) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		orderBy, err := pf.OrderBy(sorts)
+		if err != nil {
+			s.AddError(err)
+			return
+		}
+		if orderBy == "" {
+			return
+		}
+		if len(pf.Columns()) > 0 {
+			s.OrderExpr(sql.Expr(orderBy))
+			return
+		}
+
+		for _, sort := range sorts {
+			col, err := columnOf(validColumn, sort.Field)
+			if err != nil {
+				s.AddError(&prime.SortError{Field: sort.Field})
+				return
+			}
+			if sort.Order < 0 {
+				s.OrderBy(sql.Desc(s.C(col)))
+			} else {
+				s.OrderBy(sql.Asc(s.C(col)))
+			}
+		}
+	}
+}
+
+// columnOf returns the column of a field: the field itself or its snake case name.
+func columnOf(validColumn func(string) bool, field string) (string, error) {
+	if validColumn != nil {
+		if validColumn(field) {
+			return field, nil
+		}
+		if col := snakeCase(field); validColumn(col) {
+			return col, nil
+		}
+	}
+	return "", &FieldError{Field: field}
+}
+
+// snakeCase converts a camel case name to snake case, the naming of ent columns, e.g. "countryName" to "country_name".
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+			i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,194 @@
+package primeent
+
+import (
+	"context"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	prdialect "github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/prime"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// validColumn is the ValidColumn function ent generates for a customer schema.
+func validColumn(column string) bool {
+	return slices.Contains([]string{"id", "name", "country_name", "activity"}, column)
+}
+
+func newFilter(opts ...prime.Option) *prime.Filter {
+	pf := prime.New(opts...)
+	pf.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+	pf.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+	pf.RegisterFilter(filter.IN, filters.InFilter(0))
+	return pf
+}
+
+func selectCustomers(d string) *sql.Selector {
+	return sql.Dialect(d).Select("*").From(sql.Table("customers")).Where(sql.EQ("tenant_id", 7))
+}
+
+func TestWhere(t *testing.T) {
+	specs := prime.Specs{
+		"name":        {{Value: "Ja", MatchMode: filter.STARTS_WITH}},
+		"countryName": {{Value: []any{"Spain", "Italy"}, MatchMode: filter.IN}},
+		"activity":    {{Value: nil, MatchMode: filter.EQUALS}},
+	}
+	event := prime.LazyLoadEvent{SortField: "countryName", SortOrder: -1}
+
+	s := selectCustomers(dialect.Postgres)
+	Where(newFilter(), specs, validColumn)(s)
+	Order(newFilter(), event.Sorts(), validColumn)(s)
+
+	query, args := s.Query()
+	if err := s.Err(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := `SELECT * FROM "customers" WHERE "tenant_id" = $1 AND ((("customers"."country_name" IN ($2,$3))) and (("customers"."name" LIKE $4))) ` +
+		`ORDER BY "customers"."country_name" DESC`
+	if query != expected {
+		t.Errorf("expected query\n%s\ngot\n%s", expected, query)
+	}
+	if expectedArgs := []any{7, "Spain", "Italy", "Ja%"}; !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args %v, got %v", expectedArgs, args)
+	}
+
+	s = selectCustomers(dialect.MySQL)
+	Where(newFilter(), specs, validColumn)(s)
+	query, _ = s.Query()
+	if expected = "SELECT * FROM `customers` WHERE `tenant_id` = ? AND (((`customers`.`country_name` IN (?,?))) and ((`customers`.`name` LIKE ?)))"; query != expected {
+		t.Errorf("expected query\n%s\ngot\n%s", expected, query)
+	}
+}
+
+func TestWhereContext(t *testing.T) {
+	pf := newFilter()
+	pf.RegisterPredicate(func(ctx context.Context) (expr.Node, error) {
+		region, ok := ctx.Value(regionKey{}).(string)
+		if !ok {
+			return nil, errors.New("no region in context")
+		}
+		return expr.Cond("region", filter.EQUALS, region), nil
+	})
+	specs := prime.Specs{"name": {{Value: "Ja", MatchMode: filter.STARTS_WITH}}}
+
+	s := sql.Dialect(dialect.Postgres).Select("*").From(sql.Table("customers"))
+	s.WithContext(context.WithValue(context.Background(), regionKey{}, "eu"))
+	Where(pf, specs, validColumn)(s)
+
+	query, args := s.Query()
+	if expected := `SELECT * FROM "customers" WHERE ((region = $1) and ((("customers"."name" LIKE $2))))`; query != expected {
+		t.Errorf("expected query\n%s\ngot\n%s", expected, query)
+	}
+	if !reflect.DeepEqual(args, []any{"eu", "Ja%"}) {
+		t.Errorf("expected args [eu Ja%%], got %v", args)
+	}
+
+	s = sql.Dialect(dialect.Postgres).Select("*").From(sql.Table("customers"))
+	Where(pf, specs, validColumn)(s)
+	if err := s.Err(); err == nil {
+		t.Error("expected an error for a context without region, got nil")
+	}
+}
+
+type regionKey struct{}
+
+func TestWhereWithColumns(t *testing.T) {
+	pf := newFilter(prime.WithColumns(column.Configs{
+		"name":    {Field: "name", Column: "c.name", Type: column.STRING, Sortable: true},
+		"country": {Field: "country", Column: "c.country_name", Type: column.STRING},
+	}))
+
+	s := sql.Dialect(dialect.Postgres).Select("*").From(sql.Table("customers").As("c"))
+	Where(pf, prime.Specs{"country": {{Value: "Spain", MatchMode: filter.EQUALS}}}, nil)(s)
+	Order(pf, []prime.SortMeta{{Field: "name", Order: 1}}, nil)(s)
+
+	query, _ := s.Query()
+	if expected := `SELECT * FROM "customers" AS "c" WHERE (((c.country_name = $1))) ORDER BY c.name ASC`; query != expected {
+		t.Errorf("expected query\n%s\ngot\n%s", expected, query)
+	}
+}
+
+func TestWhereKeepsLiteralQuestionMarks(t *testing.T) {
+	pf := newFilter()
+	pf.RegisterFilter(filter.CONTAINS, filters.ValueFilter("?"))
+
+	s := selectCustomers(dialect.Postgres)
+	Where(pf, prime.Specs{"name": {{Value: "vip", MatchMode: filter.CONTAINS}}}, validColumn)(s)
+
+	query, args := s.Query()
+	if expected := `SELECT * FROM "customers" WHERE "tenant_id" = $1 AND ((("customers"."name" ? $2)))`; query != expected {
+		t.Errorf("expected query\n%s\ngot\n%s", expected, query)
+	}
+	if !reflect.DeepEqual(args, []any{7, "vip"}) {
+		t.Errorf("expected args [7 vip], got %v", args)
+	}
+}
+
+func TestWhereWithFilterOfAnotherDialect(t *testing.T) {
+	s := selectCustomers(dialect.MySQL)
+	Where(newFilter(prime.WithDialect(prdialect.Postgres)), prime.Specs{"name": {{Value: "Ja", MatchMode: filter.STARTS_WITH}}}, validColumn)(s)
+
+	query, args := s.Query()
+	if expected := "SELECT * FROM `customers` WHERE `tenant_id` = ? AND (((`customers`.`name` LIKE ?)))"; query != expected {
+		t.Errorf("expected query\n%s\ngot\n%s", expected, query)
+	}
+	if !reflect.DeepEqual(args, []any{7, "Ja%"}) {
+		t.Errorf("expected args [7 Ja%%], got %v", args)
+	}
+}
+
+func TestWhereErrors(t *testing.T) {
+	// ent reports the errors of a selector as text
+	tests := []struct {
+		name     string
+		pf       *prime.Filter
+		apply    func(pf *prime.Filter) func(*sql.Selector)
+		expected error
+	}{
+		{
+			name: "unknown field",
+			pf:   newFilter(),
+			apply: func(pf *prime.Filter) func(*sql.Selector) {
+				return Where(pf, prime.Specs{"password": {{Value: "x", MatchMode: filter.EQUALS}}}, validColumn)
+			},
+			expected: &FieldError{Field: "password"},
+		},
+		{
+			name: "unknown sort field",
+			pf:   newFilter(),
+			apply: func(pf *prime.Filter) func(*sql.Selector) {
+				return Order(pf, []prime.SortMeta{{Field: "password", Order: 1}}, validColumn)
+			},
+			expected: &prime.SortError{Field: "password"},
+		},
+	}
+
+	for _, test := range tests {
+		s := selectCustomers(dialect.Postgres)
+		test.apply(test.pf)(s)
+		if err := s.Err(); err == nil || err.Error() != test.expected.Error() {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expected, err)
+		}
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"name":        "name",
+		"countryName": "country_name",
+		"customerID":  "customer_id",
+		"HTTPStatus":  "http_status",
+		"address2":    "address2",
+	} {
+		if got := snakeCase(name); got != expected {
+			t.Errorf("expected %s for %s, got %s", expected, name, got)
+		}
+	}
+}
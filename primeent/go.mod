@@ -0,0 +1,12 @@
+module github.com/AdamShannag/goprime/primeent
+
+go 1.23.0
+
+replace github.com/AdamShannag/goprime => ../
+
+require (
+	entgo.io/ent v0.14.1
+	github.com/AdamShannag/goprime v0.0.0
+)
+
+require github.com/google/uuid v1.3.0 // indirect
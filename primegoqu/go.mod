@@ -0,0 +1,10 @@
+module github.com/AdamShannag/goprime/primegoqu
+
+go 1.23
+
+replace github.com/AdamShannag/goprime => ../
+
+require (
+	github.com/AdamShannag/goprime v0.0.0
+	github.com/doug-martin/goqu/v9 v9.19.0
+)
@@ -0,0 +1,58 @@
+package primegoqu
+
+import (
+	"context"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/prime"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// Where returns the condition of the Specs of a lazy load event as a goqu expression
+// that can be passed to the Where method of a dataset:
+//
+//	cond, err := primegoqu.Where(ctx, pf, specs)
+//	ds := goqu.Dialect("postgres").From("customers").Where(cond).Prepared(true)
+//
+// Parameters:
+//
+//	ctx: The context of the request, passed to the mandatory predicates and context-aware filters.
+//	pf: The Filter generating the condition.
+//	specs: The Specs of the event.
+//
+// Returns:
+//
+//	The expression, or an error as returned by WhereExpr.
+func Where(ctx context.Context, pf prime.ReadOnlyFilter, specs prime.Specs) (exp.Expression, error) {
+	return WhereExpr(ctx, pf, specs.Expr())
+}
+
+// WhereExpr validates the columns of a filter tree and returns its condition as a goqu literal expression.
+// The literal uses the "?" placeholder whatever the placeholder of the Filter, and goqu replaces it with
+// the placeholder of its dialect or interpolates it. Without any condition to apply, it returns an empty
+// expression list, which goqu leaves out of the WHERE clause.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the mandatory predicates and context-aware filters.
+//	pf: The Filter generating the condition.
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	The expression, or the error of the Filter.
+func WhereExpr(ctx context.Context, pf prime.ReadOnlyFilter, node expr.Node) (exp.Expression, error) {
+	vals, condition, err := pf.SqlExprContext(prime.WithMarkers(ctx), node)
+	if err != nil {
+		return nil, err
+	}
+	if condition == "" {
+		return exp.NewExpressionList(exp.AndType), nil
+	}
+
+	condition, err = filter.Bind(condition, vals, 1, placeholder.UnNumbered("?"))
+	if err != nil {
+		return nil, err
+	}
+	return exp.NewLiteralExpression("("+condition+")", vals...), nil
+}
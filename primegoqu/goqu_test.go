@@ -0,0 +1,73 @@
+package primegoqu
+
+import (
+	"context"
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/prime"
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+	"reflect"
+	"testing"
+)
+
+func TestWhere(t *testing.T) {
+	// a built Filter using the placeholder of the dialect can be shared with goqu
+	b := prime.NewBuilder(prime.WithDialect(dialect.Postgres))
+	b.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+	b.RegisterFilter(filter.IN, filters.InFilter(0))
+	specs := prime.Specs{
+		"name":   {{Value: "Ja", MatchMode: filter.STARTS_WITH}},
+		"status": {{Value: []any{"open", "closed"}, MatchMode: filter.IN}},
+	}
+
+	cond, err := Where(context.Background(), b.Build(), specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	query, args, err := goqu.Dialect("postgres").
+		From("customers").
+		Select("id", "name").
+		Where(goqu.C("tenant_id").Eq(7), cond).
+		Prepared(true).
+		ToSQL()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := `SELECT "id", "name" FROM "customers" WHERE (("tenant_id" = $1) AND (((name LIKE $2)) and ((status IN ($3,$4)))))`
+	if query != expected {
+		t.Errorf("expected query %s, got %s", expected, query)
+	}
+	if expectedArgs := []any{int64(7), "Ja%", "open", "closed"}; !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestWhereWithoutConditions(t *testing.T) {
+	cond, err := Where(context.Background(), prime.New(), nil)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	query, _, err := goqu.Dialect("postgres").From("customers").Where(cond).ToSQL()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if expected := `SELECT * FROM "customers"`; query != expected {
+		t.Errorf("expected query %s, got %s", expected, query)
+	}
+}
+
+func TestWhereErrors(t *testing.T) {
+	pf := prime.New(prime.WithColumns(column.Configs{"name": {Field: "name", Type: column.STRING}}))
+
+	var validationErr *prime.ValidationError
+	if _, err := Where(context.Background(), pf, prime.Specs{"password": {{Value: "x", MatchMode: filter.EQUALS}}}); !errors.As(err, &validationErr) {
+		t.Errorf("expected a *prime.ValidationError, got %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package primegorm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/prime"
+	"github.com/AdamShannag/goprime/primesql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"strings"
+)
+
+// FieldError is returned when a field of a lazy load event is not a column of the model.
+type FieldError struct {
+	Field string // Field sent by the client
+	Model string // Name of the model
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field [%s] is not a column of %s", e.Field, e.Model)
+}
+
+// Scope returns a GORM scope applying the filters, sort order and paging of a lazy load event:
+//
+//	db.WithContext(ctx).Scopes(primegorm.Scope(pf, event)).Find(&customers)
+//
+// It combines the Where, Order and Paginate scopes; errors are added to the statement.
+// Parameters:
+//
+//	pf: The Filter generating the condition and validating the sort order.
+//	event: The lazy load event of the table.
+//
+// Returns:
+//
+//	The scope.
+func Scope(pf prime.ReadOnlyFilter, event prime.LazyLoadEvent) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = Where(pf, event.Filters)(db)
+		db = Order(pf, event.Sorts())(db)
+		return Paginate(event.First, event.Rows)(db)
+	}
+}
+
+// Where returns a GORM scope adding the condition of the Specs of a lazy load event.
+// Fields are mapped to the columns registered on the Filter or, when the Filter has no registered columns,
+// to the columns of the model of the statement, looked up by json tag, field name or column name;
+// fields with a "-" json tag cannot be filtered.
+// The mapping is a rewriter passed with prime.WithRewriters, so the Filter validates the fields before they
+// are mapped, and registered rewriters see the fields sent by the client.
+// The condition uses the "?" placeholder of GORM whatever the placeholder of the Filter.
+// The context of the statement is passed to the mandatory predicates and context-aware filters.
+// Parameters:
+//
+//	pf: The Filter generating the condition.
+//	specs: The Specs of the event.
+//
+// Returns:
+//
+//	The scope, adding a *FieldError or the error of the Filter to the statement on failure.
+func Where(pf prime.ReadOnlyFilter, specs prime.Specs) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		ctx := prime.WithMarkers(db.Statement.Context)
+		if len(pf.Columns()) == 0 {
+			s, err := modelSchema(db)
+			if err != nil {
+				_ = db.AddError(err)
+				return db
+			}
+
+			ctx = prime.WithRewriters(ctx, func(n expr.Node) (expr.Node, error) {
+				c, ok := n.(*expr.Condition)
+				if !ok || c.Value == nil {
+					return n, nil
+				}
+				col, err := columnOf(db, s, c.Column)
+				c.Column = col
+				return c, err
+			})
+		}
+
+		vals, condition, err := pf.SqlContext(ctx, specs)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		if condition == "" {
+			return db
+		}
+
+		condition, err = filter.Bind(condition, vals, 1, placeholder.UnNumbered("?"))
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		return db.Where(condition, vals...)
+	}
+}
+
+// Order returns a GORM scope adding the ORDER BY clause of a sort order.
+// Fields are checked with Filter.OrderBy, and mapped to columns as in Where.
+// Parameters:
+//
+//	pf: The Filter validating the sort order.
+//	sorts: The sort order, e.g. from LazyLoadEvent.Sorts.
+//
+// Returns:
+//
+//	The scope, adding a *prime.SortError or *prime.ValidationError to the statement on failure.
+func Order(pf prime.ReadOnlyFilter, sorts []prime.SortMeta) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		orderBy, err := pf.OrderBy(sorts)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		if orderBy == "" {
+			return db
+		}
+		if len(pf.Columns()) > 0 {
+			return db.Order(orderBy)
+		}
+
+		s, err := modelSchema(db)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+
+		parts := make([]string, len(sorts))
+		for i, sort := range sorts {
+			col, err := columnOf(db, s, sort.Field)
+			if err != nil {
+				_ = db.AddError(&prime.SortError{Field: sort.Field})
+				return db
+			}
+			parts[i] = col + " ASC"
+			if sort.Order < 0 {
+				parts[i] = col + " DESC"
+			}
+		}
+		return db.Order(strings.Join(parts, ", "))
+	}
+}
+
+// Paginate returns a GORM scope limiting the rows to a page.
+// Parameters:
+//
+//	first: The index of the first row of the page.
+//	rows: The number of rows of the page; 0 for every row.
+//
+// Returns:
+//
+//	The scope.
+func Paginate(first, rows int) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if rows > 0 {
+			db = db.Limit(rows)
+		}
+		if first > 0 {
+			db = db.Offset(first)
+		}
+		return db
+	}
+}
+
+// Page loads the page of a lazy load event into a slice of T, together with the number of rows matching its filters.
+// The rows are counted with the Where scope and loaded with Scope; the rows are not loaded when nothing matches.
+// Conditions already added to db apply to both queries.
+// Parameters:
+//
+//	db: The database, e.g. db.WithContext(ctx).Where("tenant_id = ?", tenant).
+//	pf: The Filter generating the condition and validating the sort order.
+//	event: The lazy load event of the table.
+//
+// Returns:
+//
+//	The Result, or the error of a scope or query.
+func Page[T any](db *gorm.DB, pf prime.ReadOnlyFilter, event prime.LazyLoadEvent) (primesql.Result[T], error) {
+	result := primesql.Result[T]{Data: []T{}}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Model(new(T)).Scopes(Where(pf, event.Filters)).Count(&total).Error; err != nil {
+		return result, err
+	}
+	result.TotalRecords = int(total)
+	if total == 0 {
+		return result, nil
+	}
+
+	return result, db.Scopes(Scope(pf, event)).Find(&result.Data).Error
+}
+
+// modelSchema returns the schema of the model of a statement, parsing it if needed.
+func modelSchema(db *gorm.DB) (*schema.Schema, error) {
+	stmt := db.Statement
+	if stmt.Schema != nil {
+		return stmt.Schema, nil
+	}
+
+	model := stmt.Model
+	if model == nil {
+		model = stmt.Dest
+	}
+	if model == nil {
+		return nil, errors.New("primegorm: the model of the statement is not set, use db.Model")
+	}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// columnOf returns the quoted column of a field of a model, looked up by json tag, field name or column name.
+func columnOf(db *gorm.DB, s *schema.Schema, field string) (string, error) {
+	f := lookUpField(s, field)
+	if f == nil || f.DBName == "" {
+		return "", &FieldError{Field: field, Model: s.Name}
+	}
+	return db.Statement.Quote(clause.Column{Table: clause.CurrentTable, Name: f.DBName}), nil
+}
+
+// lookUpField returns the field of a schema by json tag, field name or column name,
+// leaving out the fields hidden from JSON with a "-" json tag.
+func lookUpField(s *schema.Schema, name string) *schema.Field {
+	for _, f := range s.Fields {
+		if jsonName(f) == name {
+			return f
+		}
+	}
+	if f := s.LookUpField(name); f != nil && jsonName(f) != "-" {
+		return f
+	}
+	return nil
+}
+
+func jsonName(f *schema.Field) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
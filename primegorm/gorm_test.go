@@ -0,0 +1,178 @@
+package primegorm
+
+import (
+	"context"
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/prime"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"reflect"
+	"testing"
+)
+
+type customer struct {
+	ID          uint
+	Name        string `json:"name"`
+	CountryName string `json:"country"`
+	Activity    int    `json:"activity"`
+	TenantID    int    `json:"-"`
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&customer{}); err != nil {
+		t.Fatal(err)
+	}
+
+	customers := []customer{
+		{Name: "James", CountryName: "Spain", Activity: 10, TenantID: 1},
+		{Name: "Jane", CountryName: "Italy", Activity: 80, TenantID: 1},
+		{Name: "Jack", CountryName: "Spain", Activity: 90, TenantID: 1},
+		{Name: "Amy", CountryName: "Spain", Activity: 50, TenantID: 1},
+		{Name: "Jill", CountryName: "Spain", Activity: 70, TenantID: 2},
+	}
+	if err = db.Create(&customers).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func newFilter(opts ...prime.Option) *prime.Filter {
+	pf := prime.New(opts...)
+	pf.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+	pf.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+	pf.RegisterFilter(filter.GREATER_THAN, filters.ValueFilter(">"))
+	return pf
+}
+
+func TestScope(t *testing.T) {
+	db := openDB(t)
+	event := prime.LazyLoadEvent{
+		First:     1,
+		Rows:      2,
+		SortField: "activity",
+		SortOrder: -1,
+		Filters: prime.Specs{
+			"name":    {{Value: "J", MatchMode: filter.STARTS_WITH}},
+			"country": {{Value: "Spain", MatchMode: filter.EQUALS}, {Value: nil, MatchMode: filter.STARTS_WITH}},
+		},
+	}
+
+	query := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Scopes(Scope(newFilter(), event)).Find(&[]customer{})
+	})
+	expected := "SELECT * FROM `customers` WHERE ((`customers`.`country_name` = \"Spain\")) and ((`customers`.`name` LIKE \"J%\")) " +
+		"ORDER BY `customers`.`activity` DESC LIMIT 2 OFFSET 1"
+	if query != expected {
+		t.Errorf("expected query\n%s\ngot\n%s", expected, query)
+	}
+
+	var customers []customer
+	if err := db.Where("tenant_id = ?", 1).Scopes(Scope(newFilter(), event)).Find(&customers).Error; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(customers) != 1 || customers[0].Name != "James" {
+		t.Errorf("expected [James], got %+v", customers)
+	}
+}
+
+func TestScopeWithColumns(t *testing.T) {
+	db := openDB(t)
+	pf := newFilter(prime.WithColumns(column.Configs{
+		"name":    {Field: "name", Column: "name", Type: column.STRING, Sortable: true},
+		"country": {Field: "country", Column: "country_name", Type: column.STRING},
+	}))
+	event := prime.LazyLoadEvent{
+		SortField: "name",
+		Filters:   prime.Specs{"country": {{Value: "Spain", MatchMode: filter.EQUALS}}},
+	}
+
+	query := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Scopes(Scope(pf, event)).Find(&[]customer{})
+	})
+	if expected := "SELECT * FROM `customers` WHERE ((country_name = \"Spain\")) ORDER BY name ASC"; query != expected {
+		t.Errorf("expected query\n%s\ngot\n%s", expected, query)
+	}
+}
+
+func TestPage(t *testing.T) {
+	db := openDB(t)
+	event := prime.LazyLoadEvent{
+		Rows:      2,
+		SortField: "name",
+		Filters:   prime.Specs{"activity": {{Value: 20, MatchMode: filter.GREATER_THAN}}},
+	}
+
+	result, err := Page[customer](db.WithContext(context.Background()).Where("tenant_id = ?", 1), newFilter(), event)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	names := make([]string, len(result.Data))
+	for i, c := range result.Data {
+		names[i] = c.Name
+	}
+	if result.TotalRecords != 3 || !reflect.DeepEqual(names, []string{"Amy", "Jack"}) {
+		t.Errorf("expected 3 records and [Amy Jack], got %d and %v", result.TotalRecords, names)
+	}
+
+	event.Filters = prime.Specs{"name": {{Value: "Z", MatchMode: filter.STARTS_WITH}}}
+	result, err = Page[customer](db, newFilter(), event)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if result.TotalRecords != 0 || result.Data == nil || len(result.Data) != 0 {
+		t.Errorf("expected an empty page, got %+v", result)
+	}
+}
+
+func TestScopeErrors(t *testing.T) {
+	db := openDB(t)
+
+	var fieldErr *FieldError
+	event := prime.LazyLoadEvent{Filters: prime.Specs{"password": {{Value: "x", MatchMode: filter.EQUALS}}}}
+	if err := db.Scopes(Scope(newFilter(), event)).Find(&[]customer{}).Error; !errors.As(err, &fieldErr) {
+		t.Errorf("expected a *FieldError, got %v", err)
+	}
+
+	event = prime.LazyLoadEvent{Filters: prime.Specs{"TenantID": {{Value: 2, MatchMode: filter.EQUALS}}}}
+	if err := db.Scopes(Scope(newFilter(), event)).Find(&[]customer{}).Error; !errors.As(err, &fieldErr) {
+		t.Errorf("expected a *FieldError for a field hidden from JSON, got %v", err)
+	}
+
+	var sortErr *prime.SortError
+	event = prime.LazyLoadEvent{SortField: "password"}
+	if err := db.Scopes(Scope(newFilter(), event)).Find(&[]customer{}).Error; !errors.As(err, &sortErr) {
+		t.Errorf("expected a *prime.SortError, got %v", err)
+	}
+
+	var modeErr *prime.MatchModeError
+	pf := newFilter()
+	pf.RegisterColumnModes("name", filter.EQUALS)
+	event = prime.LazyLoadEvent{Filters: prime.Specs{"name": {{Value: "J", MatchMode: filter.STARTS_WITH}}}}
+	if _, err := Page[customer](db, pf, event); !errors.As(err, &modeErr) {
+		t.Errorf("expected a *prime.MatchModeError, got %v", err)
+	}
+}
+
+func TestWhereWithFilterOfAnotherDialect(t *testing.T) {
+	db := openDB(t)
+
+	var customers []customer
+	specs := prime.Specs{"name": {{Value: "Ja", MatchMode: filter.STARTS_WITH}}, "activity": {{Value: 20, MatchMode: filter.GREATER_THAN}}}
+	if err := db.Scopes(Where(newFilter(prime.WithDialect(dialect.Postgres)), specs)).Find(&customers).Error; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(customers) != 2 || customers[0].Name != "Jane" || customers[1].Name != "Jack" {
+		t.Errorf("expected Jane and Jack, got %+v", customers)
+	}
+}
@@ -0,0 +1,195 @@
+package primehttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/AdamShannag/goprime/prime"
+	"github.com/AdamShannag/goprime/primeurl"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MaxBodyBytes is the maximum size of a request body holding a lazy load event.
+const MaxBodyBytes = 1 << 20
+
+// Query is the parsed and validated lazy load request of a PrimeNG table.
+type Query struct {
+	Event     prime.LazyLoadEvent // Lazy load event sent by the table
+	Condition string              // SQL condition of the filters; empty if nothing is filtered
+	Vals      []any               // Values bound by the placeholders of Condition
+	OrderBy   string              // ORDER BY list of the sort order; empty if the table is not sorted
+	Limit     int                 // Number of rows to load; 0 to load every row
+	Offset    int                 // Index of the first row to load
+}
+
+// RequestError is returned when a request does not hold a well-formed lazy load event.
+type RequestError struct {
+	Param string // Query parameter holding the invalid value; empty for the request body
+	Err   error  // Error encountered while decoding the value
+}
+
+func (e *RequestError) Error() string {
+	if e.Param == "" {
+		return fmt.Sprintf("invalid request body: %s", e.Err.Error())
+	}
+	return fmt.Sprintf("invalid query parameter [%s]: %s", e.Param, e.Err.Error())
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+type queryKey struct{}
+
+// Parse decodes the lazy load event of a request, validates it and generates its SQL condition and sort order.
+// The event is decoded from the JSON body of the request, or from its query parameters if it has no body.
+// Query parameters are either the compact encoding of primeurl, with values typed by the registered columns,
+// or the fields of the event: "filters" and "multiSortMeta" hold JSON, and "first", "rows", "sortField",
+// "sortOrder" and "globalFilter" hold plain values,
+// e.g. ?first=0&rows=10&sortField=name&filters={"name":[{"value":"Ja","matchMode":"startsWith"}]}.
+// Parameters:
+//
+//	r: The HTTP request; its context is passed to the mandatory predicates and context-aware filters.
+//	pf: The Filter validating the event and generating the SQL condition.
+//
+// Returns:
+//
+//	The Query, or a *RequestError if the event is malformed, or the validation error of the Filter.
+func Parse(r *http.Request, pf prime.ReadOnlyFilter) (*Query, error) {
+	event, err := decode(r, pf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateSpecs(pf, event.Filters); err != nil {
+		return nil, err
+	}
+
+	vals, condition, err := pf.SqlContext(r.Context(), event.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy, err := pf.OrderBy(event.Sorts())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Query{
+		Event:     event,
+		Condition: condition,
+		Vals:      vals,
+		OrderBy:   orderBy,
+		Limit:     event.Rows,
+		Offset:    event.First,
+	}, nil
+}
+
+// Middleware parses the lazy load event of every request with Parse and stores the Query in the request context,
+// where handlers retrieve it with FromContext. Requests that cannot be parsed are answered with
+// a problem+json error written by WriteError and do not reach the next handler.
+// Parameters:
+//
+//	pf: The Filter validating the events and generating the SQL conditions.
+//
+// Returns:
+//
+//	The middleware.
+func Middleware(pf prime.ReadOnlyFilter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query, err := Parse(r, pf)
+			if err != nil {
+				WriteError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), query)))
+		})
+	}
+}
+
+// NewContext returns a copy of ctx carrying a Query.
+func NewContext(ctx context.Context, query *Query) context.Context {
+	return context.WithValue(ctx, queryKey{}, query)
+}
+
+// FromContext returns the Query stored in ctx by Middleware.
+func FromContext(ctx context.Context) (*Query, bool) {
+	query, ok := ctx.Value(queryKey{}).(*Query)
+	return query, ok
+}
+
+func decode(r *http.Request, pf prime.ReadOnlyFilter) (prime.LazyLoadEvent, error) {
+	var event prime.LazyLoadEvent
+	if r.Body == nil || r.Body == http.NoBody || r.Method == http.MethodGet || r.Method == http.MethodHead {
+		values := r.URL.Query()
+		if values.Has("filters") || values.Has("multiSortMeta") || values.Has("sortField") || values.Has("globalFilter") {
+			return event, decodeQuery(values, &event)
+		}
+
+		event, err := primeurl.New(pf.Columns()).Decode(r.URL.RawQuery)
+		var urlErr *primeurl.Error
+		if errors.As(err, &urlErr) {
+			return event, &RequestError{Param: urlErr.Param, Err: urlErr.Err}
+		}
+		return event, err
+	}
+
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, MaxBodyBytes)).Decode(&event); err != nil {
+		return event, &RequestError{Err: err}
+	}
+	if event.First < 0 || event.Rows < 0 {
+		return event, &RequestError{Err: errors.New("first and rows must not be negative")}
+	}
+	return event, nil
+}
+
+func decodeQuery(values url.Values, event *prime.LazyLoadEvent) error {
+	for param, target := range map[string]any{"filters": &event.Filters, "multiSortMeta": &event.MultiSortMeta} {
+		if v := values.Get(param); v != "" {
+			if err := json.Unmarshal([]byte(v), target); err != nil {
+				return &RequestError{Param: param, Err: err}
+			}
+		}
+	}
+
+	for param, target := range map[string]*int{"first": &event.First, "rows": &event.Rows, "sortOrder": &event.SortOrder} {
+		if v := values.Get(param); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return &RequestError{Param: param, Err: err}
+			}
+			if n < 0 && param != "sortOrder" {
+				return &RequestError{Param: param, Err: errors.New("must not be negative")}
+			}
+			*target = n
+		}
+	}
+
+	event.SortField = values.Get("sortField")
+	if v := values.Get("globalFilter"); v != "" {
+		event.GlobalFilter = v
+	}
+	return nil
+}
+
+// validateSpecs rejects constraints the Filter cannot render, so they are reported as client errors.
+func validateSpecs(pf prime.ReadOnlyFilter, specs prime.Specs) error {
+	for col, constraints := range specs {
+		for _, s := range constraints {
+			if !pf.HasFilter(s.MatchMode) {
+				return &prime.MatchModeError{Column: col, MatchMode: s.MatchMode}
+			}
+			switch strings.ToLower(s.Operator) {
+			case "", "and", "or":
+			default:
+				return &RequestError{Param: "filters", Err: fmt.Errorf("unknown operator [%s] on column [%s]", s.Operator, col)}
+			}
+		}
+	}
+	return nil
+}
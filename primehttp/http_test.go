@@ -0,0 +1,214 @@
+package primehttp
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/prime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newFilter() prime.ReadOnlyFilter {
+	b := prime.NewBuilder(prime.WithPlaceholder(placeholder.Numbered("$")))
+	b.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+	b.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+	b.RegisterFilter(filter.IN, filters.InFilter(0))
+	b.RegisterFilter(filter.BETWEEN, filters.BetweenFilter(0))
+	b.RegisterColumns(column.Configs{
+		"name":   {Field: "name", Column: "c.name", Type: column.STRING, Sortable: true},
+		"status": {Field: "status", Type: column.STRING, Modes: []filter.MatchMode{filter.EQUALS, filter.IN}},
+	})
+	b.SetLimits(prime.Limits{MaxListValues: 3})
+	return b.Build()
+}
+
+func serve(t *testing.T, r *http.Request) (*Query, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	var query *Query
+	handler := Middleware(newFilter())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, _ = FromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return query, w
+}
+
+func TestMiddlewareBody(t *testing.T) {
+	body := `{
+		"first": 20, "rows": 10, "sortField": "name", "sortOrder": -1,
+		"filters": {
+			"name": [{"value": "Ja", "matchMode": "startsWith", "operator": "and"}],
+			"status": [{"value": ["open", "new"], "matchMode": "in", "operator": "and"}]
+		}
+	}`
+
+	query, w := serve(t, httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(body)))
+	if query == nil {
+		t.Fatalf("expected a query in the context, got status %d: %s", w.Code, w.Body)
+	}
+
+	expectedCondition := "((c.name LIKE $1)) and ((status IN ($2,$3)))"
+	if query.Condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, query.Condition)
+	}
+	if !reflect.DeepEqual(query.Vals, []any{"Ja%", "open", "new"}) {
+		t.Errorf("expected values [Ja%% open new], got %v", query.Vals)
+	}
+	if query.OrderBy != "c.name DESC" {
+		t.Errorf("expected ORDER BY c.name DESC, got %s", query.OrderBy)
+	}
+	if query.Limit != 10 || query.Offset != 20 {
+		t.Errorf("expected limit 10 and offset 20, got %d and %d", query.Limit, query.Offset)
+	}
+}
+
+func TestMiddlewareQueryString(t *testing.T) {
+	params := url.Values{
+		"first":     {"0"},
+		"rows":      {"5"},
+		"sortField": {"name"},
+		"filters":   {`{"status":[{"value":"open","matchMode":"equals"}]}`},
+	}
+
+	query, w := serve(t, httptest.NewRequest(http.MethodGet, "/customers?"+params.Encode(), nil))
+	if query == nil {
+		t.Fatalf("expected a query in the context, got status %d: %s", w.Code, w.Body)
+	}
+
+	if query.Condition != "((status = $1))" || query.OrderBy != "c.name ASC" || query.Limit != 5 {
+		t.Errorf("unexpected query %+v", query)
+	}
+}
+
+func TestMiddlewareProblems(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   *http.Request
+		status    int
+		column    string
+		matchMode filter.MatchMode
+	}{
+		{
+			name:    "malformed body",
+			request: httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(`{"filters": [`)),
+			status:  http.StatusBadRequest,
+		},
+		{
+			name:    "malformed parameter",
+			request: httptest.NewRequest(http.MethodGet, "/customers?rows=ten", nil),
+			status:  http.StatusBadRequest,
+		},
+		{
+			name:    "body too large",
+			request: httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(`{"globalFilter": "`+strings.Repeat("a", MaxBodyBytes)+`"}`)),
+			status:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:      "unknown column",
+			request:   httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(`{"filters": {"password": [{"value": "x", "matchMode": "equals"}]}}`)),
+			status:    http.StatusUnprocessableEntity,
+			column:    "password",
+			matchMode: filter.EQUALS,
+		},
+		{
+			name:      "match mode not allowed",
+			request:   httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(`{"filters": {"status": [{"value": "o", "matchMode": "startsWith"}]}}`)),
+			status:    http.StatusUnprocessableEntity,
+			column:    "status",
+			matchMode: filter.STARTS_WITH,
+		},
+		{
+			name:      "match mode not registered",
+			request:   httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(`{"filters": {"name": [{"value": "o", "matchMode": "regex"}]}}`)),
+			status:    http.StatusUnprocessableEntity,
+			column:    "name",
+			matchMode: "regex",
+		},
+		{
+			name:      "invalid value",
+			request:   httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(`{"filters": {"name": [{"value": ["a"], "matchMode": "between"}]}}`)),
+			status:    http.StatusUnprocessableEntity,
+			column:    "name",
+			matchMode: filter.BETWEEN,
+		},
+		{
+			name:    "limit exceeded",
+			request: httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(`{"filters": {"status": [{"value": ["a", "b", "c", "d"], "matchMode": "in"}]}}`)),
+			status:  http.StatusUnprocessableEntity,
+			column:  "status",
+		},
+		{
+			name:    "not sortable",
+			request: httptest.NewRequest(http.MethodGet, "/customers?sortField=status", nil),
+			status:  http.StatusUnprocessableEntity,
+			column:  "status",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, w := serve(t, test.request)
+			if query != nil {
+				t.Fatal("expected the request not to reach the handler")
+			}
+
+			if w.Code != test.status {
+				t.Errorf("expected status %d, got %d", test.status, w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+				t.Errorf("expected content type application/problem+json, got %s", contentType)
+			}
+
+			var p Problem
+			if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+				t.Fatalf("expected a problem, got %v", err)
+			}
+			if p.Status != test.status || p.Column != test.column || p.MatchMode != test.matchMode || p.Instance == "" {
+				t.Errorf("unexpected problem %+v", p)
+			}
+		})
+	}
+}
+
+func TestProblemForUnknownError(t *testing.T) {
+	b := prime.NewBuilder(prime.WithPlaceholder(placeholder.Numbered("$")))
+	b.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+	b.RegisterPredicate(func(ctx context.Context) (expr.Node, error) {
+		return nil, context.Canceled
+	})
+	pf := b.Build()
+
+	r := httptest.NewRequest(http.MethodGet, "/customers", nil)
+	if _, err := Parse(r, pf); err == nil {
+		t.Fatal("expected the error of the predicate, got nil")
+	} else if p := ProblemFor(err); p.Status != http.StatusInternalServerError || p.Detail != "" {
+		t.Errorf("expected an undisclosed internal server error, got %+v", p)
+	}
+}
+
+func TestMiddlewareCompactQueryString(t *testing.T) {
+	query, w := serve(t, httptest.NewRequest(http.MethodGet, "/customers?filter[status][in]=open,new&sort=-name&rows=5", nil))
+	if query == nil {
+		t.Fatalf("expected a query in the context, got status %d: %s", w.Code, w.Body)
+	}
+
+	if query.Condition != "((status IN ($1,$2)))" || query.OrderBy != "c.name DESC" || query.Limit != 5 {
+		t.Errorf("unexpected query %+v", query)
+	}
+
+	_, w = serve(t, httptest.NewRequest(http.MethodGet, "/customers?filter[status]=open", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,97 @@
+package primehttp
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/prime"
+	"net/http"
+)
+
+// Problem is an RFC 7807 problem details object, extended with the column and match mode
+// of the constraint that caused the problem.
+type Problem struct {
+	Type      string           `json:"type"`                // URI identifying the problem type
+	Title     string           `json:"title"`               // Short summary of the problem type
+	Status    int              `json:"status"`              // HTTP status code
+	Detail    string           `json:"detail,omitempty"`    // Explanation of this occurrence of the problem
+	Instance  string           `json:"instance,omitempty"`  // URI of the request that caused the problem
+	Column    string           `json:"column,omitempty"`    // Column of the failing constraint
+	MatchMode filter.MatchMode `json:"matchMode,omitempty"` // Match mode of the failing constraint
+}
+
+// ProblemFor maps an error returned by Parse to a Problem:
+//
+//	*RequestError:                            400 Bad Request
+//	*http.MaxBytesError:                      413 Request Entity Too Large
+//	*prime.ValidationError, *prime.MatchModeError,
+//	*prime.LimitError, *prime.SortError,
+//	*prime.ValueError:                        422 Unprocessable Entity
+//
+// Any other error is a 500 Internal Server Error, whose detail is not disclosed to the client.
+// Parameters:
+//
+//	err: The error to map.
+//
+// Returns:
+//
+//	The Problem describing the error.
+func ProblemFor(err error) Problem {
+	var (
+		requestErr    *RequestError
+		tooLargeErr   *http.MaxBytesError
+		validationErr *prime.ValidationError
+		modeErr       *prime.MatchModeError
+		limitErr      *prime.LimitError
+		sortErr       *prime.SortError
+		valueErr      *prime.ValueError
+	)
+
+	switch {
+	case errors.As(err, &tooLargeErr):
+		return newProblem(http.StatusRequestEntityTooLarge, "Request body too large", err)
+	case errors.As(err, &requestErr):
+		return newProblem(http.StatusBadRequest, "Malformed filter request", err)
+	case errors.As(err, &validationErr):
+		p := newProblem(http.StatusUnprocessableEntity, "Invalid filter", err)
+		p.Column, p.MatchMode = validationErr.Column, validationErr.MatchMode
+		return p
+	case errors.As(err, &modeErr):
+		p := newProblem(http.StatusUnprocessableEntity, "Match mode not allowed", err)
+		p.Column, p.MatchMode = modeErr.Column, modeErr.MatchMode
+		return p
+	case errors.As(err, &limitErr):
+		p := newProblem(http.StatusUnprocessableEntity, "Filter too complex", err)
+		p.Column = limitErr.Column
+		return p
+	case errors.As(err, &sortErr):
+		p := newProblem(http.StatusUnprocessableEntity, "Invalid sort order", err)
+		p.Column = sortErr.Field
+		return p
+	case errors.As(err, &valueErr):
+		p := newProblem(http.StatusUnprocessableEntity, "Invalid filter value", err)
+		p.Column, p.MatchMode = valueErr.Column, valueErr.MatchMode
+		return p
+	default:
+		return Problem{Type: "about:blank", Title: http.StatusText(http.StatusInternalServerError), Status: http.StatusInternalServerError}
+	}
+}
+
+func newProblem(status int, title string, err error) Problem {
+	return Problem{Type: "about:blank", Title: title, Status: status, Detail: err.Error()}
+}
+
+// WriteError writes the Problem of an error as an "application/problem+json" response.
+// Parameters:
+//
+//	w: The response writer.
+//	r: The request that caused the error; its URL is the instance of the problem.
+//	err: The error, e.g. returned by Parse.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	p := ProblemFor(err)
+	p.Instance = r.URL.RequestURI()
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
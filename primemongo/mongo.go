@@ -0,0 +1,258 @@
+package primemongo
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/prime"
+	"maps"
+	"strings"
+)
+
+// Renderer turns filter specifications into MongoDB query documents.
+// It shares its filter contract with a prime.Filter: only the match modes registered on the
+// prime.Filter are accepted, and its column validators, allowed match modes, limits and rewriters are
+// applied before rendering.
+// The returned documents are plain map[string]any values and can be used wherever a bson.M is expected.
+type Renderer struct {
+	filter    prime.ReadOnlyFilter          // Filter providing the match modes, column validators and rewriters
+	operators map[filter.MatchMode]Operator // Map of match modes to their corresponding MongoDB operators
+}
+
+// New creates a new Renderer for the given prime.Filter, initialized with DefaultOperators.
+// Parameters:
+//
+//	pf: The prime.Filter whose match modes, column validators and rewriters are used.
+//
+// Returns:
+//
+//	A pointer to a newly created Renderer instance.
+func New(pf prime.ReadOnlyFilter) *Renderer {
+	return &Renderer{filter: pf, operators: DefaultOperators()}
+}
+
+// RegisterOperator adds or replaces the MongoDB operator for a specific match mode.
+// Parameters:
+//
+//	matchMode: The match mode for which to register the operator.
+//	operator: The operator to be registered for the specified match mode.
+func (r *Renderer) RegisterOperator(matchMode filter.MatchMode, operator Operator) {
+	r.operators[matchMode] = operator
+}
+
+// Query generates a MongoDB query document based on the provided Specs.
+// Parameters:
+//
+//	specs: The Specs object that provides the specifications for generating the query.
+//
+// Returns:
+//
+//	A bson.M compatible query document, or an error if a column is invalid, a match mode is not
+//	registered or a value is not supported by its operator.
+func (r *Renderer) Query(specs prime.Specs) (map[string]any, error) {
+	return r.QueryExprContext(context.Background(), specs.Expr())
+}
+
+// QueryContext generates a MongoDB query document based on the provided Specs, ANDed with the
+// mandatory predicates registered on the prime.Filter and carried by the context.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the predicates of the prime.Filter.
+//	specs: The Specs object that provides the specifications for generating the query.
+//
+// Returns:
+//
+//	A bson.M compatible query document, or an error as returned by Query or by a failing predicate.
+func (r *Renderer) QueryContext(ctx context.Context, specs prime.Specs) (map[string]any, error) {
+	return r.QueryExprContext(ctx, specs.Expr())
+}
+
+// QueryExpr generates a MongoDB query document from a filter tree.
+// AND groups are merged into a single document when their conditions do not overlap,
+// e.g. a dateAfter and a dateBefore on the same field become one range.
+// Parameters:
+//
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	A bson.M compatible query document; an empty document matches everything.
+func (r *Renderer) QueryExpr(node expr.Node) (map[string]any, error) {
+	return r.QueryExprContext(context.Background(), node)
+}
+
+// QueryExprContext generates a MongoDB query document from a filter tree, ANDed with the mandatory predicates
+// for the context. The filter tree is validated and rewritten before the predicates are added,
+// so the client can neither negate nor bypass them.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the predicates of the prime.Filter.
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	A bson.M compatible query document, or an error as returned by QueryExpr or by a failing predicate.
+func (r *Renderer) QueryExprContext(ctx context.Context, node expr.Node) (map[string]any, error) {
+	if err := r.filter.ValidateExpr(node); err != nil {
+		return nil, err
+	}
+
+	if err := r.filter.ValidateModes(node); err != nil {
+		return nil, err
+	}
+
+	if err := r.filter.ValidateLimits(node); err != nil {
+		return nil, err
+	}
+
+	node, err := r.filter.RewriteContext(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err = r.filter.Scope(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := r.render(node)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return doc, nil
+}
+
+func (r *Renderer) render(node expr.Node) (map[string]any, error) {
+	switch n := node.(type) {
+	case *expr.Condition:
+		return r.renderCondition(n)
+	case *expr.Group:
+		return r.renderGroup(n)
+	case *expr.Not:
+		inner, err := r.render(n.Node)
+		if err != nil || inner == nil {
+			return nil, err
+		}
+		return map[string]any{"$nor": []any{inner}}, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown node type [%T]", node)
+	}
+}
+
+func (r *Renderer) renderGroup(g *expr.Group) (map[string]any, error) {
+	if g.Operator != expr.AND && g.Operator != expr.OR {
+		return nil, fmt.Errorf("unknown operator [%s]", g.Operator)
+	}
+
+	docs := make([]map[string]any, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		doc, err := r.render(n)
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+
+	switch {
+	case len(docs) == 0:
+		return nil, nil
+	case len(docs) == 1:
+		return docs[0], nil
+	case g.Operator == expr.AND:
+		return merge(docs), nil
+	default:
+		return map[string]any{"$or": list(docs)}, nil
+	}
+}
+
+func (r *Renderer) renderCondition(c *expr.Condition) (map[string]any, error) {
+	if !r.filter.HasFilter(c.MatchMode) {
+		return nil, fmt.Errorf("match mode not registered [%s]", c.MatchMode)
+	}
+
+	operator, ok := r.operators[c.MatchMode]
+	if !ok {
+		return nil, fmt.Errorf("no mongo operator for match mode [%s]", c.MatchMode)
+	}
+
+	if c.Value == nil {
+		return nil, nil
+	}
+
+	// a field starting with "$" would be read as a top-level operator, e.g. $where or $expr
+	if strings.HasPrefix(c.Column, "$") {
+		return nil, fmt.Errorf("invalid field [%s]", c.Column)
+	}
+
+	doc, err := operator.Query(c.Column, c.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.Column, err)
+	}
+	return doc, nil
+}
+
+// merge combines the documents of an AND group into one document, falling back to $and
+// when two documents constrain the same field with the same operator.
+func merge(docs []map[string]any) map[string]any {
+	merged := make(map[string]any)
+	for _, doc := range docs {
+		for field, value := range doc {
+			existing, ok := merged[field]
+			if !ok {
+				merged[field] = value
+				continue
+			}
+			combined, ok := mergeOperators(existing, value)
+			if !ok {
+				return map[string]any{"$and": list(docs)}
+			}
+			merged[field] = combined
+		}
+	}
+	return merged
+}
+
+func mergeOperators(a, b any) (map[string]any, bool) {
+	ma, ok := a.(map[string]any)
+	if !ok || !operatorsOnly(ma) {
+		return nil, false
+	}
+	mb, ok := b.(map[string]any)
+	if !ok || !operatorsOnly(mb) {
+		return nil, false
+	}
+
+	combined := maps.Clone(ma)
+	for k, v := range mb {
+		if _, exists := combined[k]; exists {
+			return nil, false
+		}
+		combined[k] = v
+	}
+	return combined, true
+}
+
+func operatorsOnly(m map[string]any) bool {
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+	return len(m) > 0
+}
+
+func list(docs []map[string]any) []any {
+	l := make([]any, len(docs))
+	for i, d := range docs {
+		l[i] = d
+	}
+	return l
+}
@@ -0,0 +1,272 @@
+package primemongo
+
+import (
+	"context"
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/prime"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newRenderer() *Renderer {
+	pf := prime.New(prime.WithPlaceholder(placeholder.Numbered("$")))
+	pf.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+	pf.RegisterFilter(filter.CONTAINS, filters.NewPatternMatchFilter("LIKE", filters.AROUND))
+	pf.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+	pf.RegisterFilter(filter.GREATER_THAN, filters.ValueFilter(">"))
+	pf.RegisterFilter(filter.IN, filters.InFilter(0))
+	pf.RegisterFilter(filter.BETWEEN, filters.BetweenFilter(0))
+	pf.RegisterFilter(filter.DATE_AFTER, filters.DateAfterFilter(0))
+	pf.RegisterFilter(filter.DATE_BEFORE, filters.DateBeforeFilter(0))
+	pf.RegisterColumnValidator(column.AllowedValidator{"name", "representative", "date", "activity", "status", "priority"})
+	return New(pf)
+}
+
+func TestQuery(t *testing.T) {
+	specs := prime.Specs{
+		"name": {
+			{Value: "Ja.", MatchMode: filter.STARTS_WITH, Operator: "and"},
+		},
+		"representative": {
+			{Value: []any{"Amy Elsner", "Anna Fali"}, MatchMode: filter.IN, Operator: "and"},
+		},
+		"date": {
+			{Value: "2024-08-12T21:00:00.000Z", MatchMode: filter.DATE_AFTER, Operator: "and"},
+			{Value: "2024-08-20T21:00:00.000Z", MatchMode: filter.DATE_BEFORE, Operator: "and"},
+		},
+		"activity": {
+			{Value: []any{68, 100}, MatchMode: filter.BETWEEN, Operator: "and"},
+		},
+	}
+
+	query, err := newRenderer().Query(specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"name":           map[string]any{"$regex": `^Ja\.`},
+		"representative": map[string]any{"$in": []any{"Amy Elsner", "Anna Fali"}},
+		"date": map[string]any{
+			"$gt": time.Date(2024, 8, 12, 21, 0, 0, 0, time.UTC),
+			"$lt": time.Date(2024, 8, 20, 21, 0, 0, 0, time.UTC),
+		},
+		"activity": map[string]any{"$gte": 68, "$lte": 100},
+	}
+
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v, got %v", expected, query)
+	}
+}
+
+func TestQueryDateRange(t *testing.T) {
+	specs := prime.Specs{
+		"date": {{Value: []any{"2024-08-12T21:00:00.000Z", "2024-08-20T21:00:00.000Z"}, MatchMode: filter.BETWEEN}},
+	}
+
+	query, err := newRenderer().Query(specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"date": map[string]any{
+			"$gte": time.Date(2024, 8, 12, 21, 0, 0, 0, time.UTC),
+			"$lte": time.Date(2024, 8, 20, 21, 0, 0, 0, time.UTC),
+		},
+	}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v, got %v", expected, query)
+	}
+}
+
+func TestQueryExpr(t *testing.T) {
+	tree := expr.And(
+		expr.Or(expr.Cond("status", filter.EQUALS, "open"), expr.Cond("priority", filter.GREATER_THAN, 3)),
+		&expr.Not{Node: expr.Cond("name", filter.CONTAINS, "bot")},
+	)
+
+	query, err := newRenderer().QueryExpr(tree)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"$or": []any{
+			map[string]any{"status": "open"},
+			map[string]any{"priority": map[string]any{"$gt": 3}},
+		},
+		"$nor": []any{
+			map[string]any{"name": map[string]any{"$regex": "bot"}},
+		},
+	}
+
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v, got %v", expected, query)
+	}
+}
+
+func TestQueryFallsBackToAnd(t *testing.T) {
+	specs := prime.Specs{
+		"name": {
+			{Value: "Ja", MatchMode: filter.STARTS_WITH, Operator: "and"},
+			{Value: "es", MatchMode: filter.CONTAINS, Operator: "and"},
+		},
+	}
+
+	query, err := newRenderer().Query(specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"$and": []any{
+			map[string]any{"name": map[string]any{"$regex": "^Ja"}},
+			map[string]any{"name": map[string]any{"$regex": "es"}},
+		},
+	}
+
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v, got %v", expected, query)
+	}
+}
+
+func TestQueryEmpty(t *testing.T) {
+	query, err := newRenderer().Query(prime.Specs{"name": {{Value: nil, MatchMode: filter.STARTS_WITH}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(query) != 0 {
+		t.Errorf("expected an empty query, got %v", query)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs prime.Specs
+	}{
+		{"invalid column", prime.Specs{"password": {{Value: "a", MatchMode: filter.EQUALS}}}},
+		{"unregistered match mode", prime.Specs{"name": {{Value: "a", MatchMode: filter.ENDS_WITH}}}},
+		{"invalid between value", prime.Specs{"activity": {{Value: 68, MatchMode: filter.BETWEEN}}}},
+		{"invalid date", prime.Specs{"date": {{Value: "yesterday", MatchMode: filter.DATE_AFTER}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := newRenderer().Query(test.specs); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestQueryRejectsOperatorInjection(t *testing.T) {
+	pf := prime.New()
+	pf.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+	pf.RegisterFilter(filter.DATE_IS, filters.DateIsFilter(0))
+	mr := New(pf)
+
+	tests := []struct {
+		name  string
+		specs prime.Specs
+	}{
+		{"operator value", prime.Specs{"password": {{Value: map[string]any{"$ne": nil}, MatchMode: filter.EQUALS}}}},
+		{"regex value", prime.Specs{"name": {{Value: map[string]any{"$regex": ".*"}, MatchMode: filter.EQUALS}}}},
+		{"date operator value", prime.Specs{"date": {{Value: map[string]any{"$gt": 0}, MatchMode: filter.DATE_IS}}}},
+		{"where field", prime.Specs{"$where": {{Value: "sleep(1000)", MatchMode: filter.EQUALS}}}},
+		{"expr field", prime.Specs{"$expr": {{Value: "a", MatchMode: filter.EQUALS}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if query, err := mr.Query(test.specs); err == nil {
+				t.Errorf("expected an error, got query %v", query)
+			}
+		})
+	}
+}
+
+func TestRegisterOperator(t *testing.T) {
+	r := newRenderer()
+	r.RegisterOperator(filter.STARTS_WITH, RegexOperator{Format: "^%s", Options: "i"})
+
+	query, err := r.Query(prime.Specs{"name": {{Value: "ja", MatchMode: filter.STARTS_WITH}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := map[string]any{"name": map[string]any{"$regex": "^ja", "$options": "i"}}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v, got %v", expected, query)
+	}
+}
+
+func TestQueryRejectsDisallowedMatchMode(t *testing.T) {
+	r := newRenderer()
+	r.filter.(*prime.Filter).RegisterColumnModes("name", filter.EQUALS)
+
+	_, err := r.Query(prime.Specs{"name": {{Value: "Ja", MatchMode: filter.STARTS_WITH}}})
+
+	var modeErr *prime.MatchModeError
+	if !errors.As(err, &modeErr) {
+		t.Errorf("expected a *prime.MatchModeError, got %v", err)
+	}
+}
+
+func TestQueryContext(t *testing.T) {
+	r := newRenderer()
+	r.filter.(*prime.Filter).RegisterPredicate(func(ctx context.Context) (expr.Node, error) {
+		return expr.Cond("tenant_id", filter.EQUALS, "acme"), nil
+	})
+
+	specs := prime.Specs{
+		"tenant_id": {{Value: "other", MatchMode: filter.EQUALS}},
+	}
+	if _, err := r.QueryContext(context.Background(), specs); err == nil {
+		t.Error("expected the client to be unable to filter on the tenant column, got nil")
+	}
+
+	specs = prime.Specs{
+		"status": {{Value: "open", MatchMode: filter.EQUALS}},
+	}
+	query, err := r.QueryContext(context.Background(), specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := map[string]any{
+		"tenant_id": "acme",
+		"status":    "open",
+	}
+
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v, got %v", expected, query)
+	}
+}
+
+func TestQueryContextRewriters(t *testing.T) {
+	ctx := prime.WithRewriters(context.Background(), func(n expr.Node) (expr.Node, error) {
+		if c, ok := n.(*expr.Condition); ok && c.Column == "name" {
+			c.Column = "profile.name"
+		}
+		return n, nil
+	})
+
+	query, err := newRenderer().QueryContext(ctx, prime.Specs{"name": {{Value: "James", MatchMode: filter.EQUALS}}})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := map[string]any{"profile.name": "James"}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v, got %v", expected, query)
+	}
+}
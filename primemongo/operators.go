@@ -0,0 +1,152 @@
+package primemongo
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/filter"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// Operator builds the MongoDB query document for a single condition.
+type Operator interface {
+	// Query creates the query document for a field.
+	// Parameters:
+	//   field: The name of the document field to filter.
+	//   value: The value of the condition; a list value is passed as []any.
+	// Returns:
+	//   A bson.M compatible query document, or an error if the value is not supported.
+	Query(field string, value any) (map[string]any, error)
+}
+
+// OperatorFunc is an adapter to allow the use of ordinary functions as an Operator.
+type OperatorFunc func(field string, value any) (map[string]any, error)
+
+// Query calls f(field, value).
+func (f OperatorFunc) Query(field string, value any) (map[string]any, error) {
+	return f(field, value)
+}
+
+// ComparisonOperator compares a field with a single value using a MongoDB comparison operator, e.g. "$gt".
+// An empty ComparisonOperator matches the value by equality.
+type ComparisonOperator string
+
+// Query creates {field: value} for an empty operator and {field: {operator: value}} otherwise.
+// Object values are rejected, as MongoDB would read their keys as operators, e.g. {"$ne": null}.
+func (o ComparisonOperator) Query(field string, value any) (map[string]any, error) {
+	if value != nil && reflect.TypeOf(value).Kind() == reflect.Map {
+		return nil, fmt.Errorf("comparison value must not be an object, got [%v]", value)
+	}
+	if o == "" {
+		return map[string]any{field: value}, nil
+	}
+	return map[string]any{field: map[string]any{string(o): value}}, nil
+}
+
+// DateOperator compares a field with a date using a MongoDB comparison operator.
+// String values are parsed as RFC 3339 timestamps, the format PrimeNG sends dates in,
+// so they are compared as BSON dates instead of strings.
+type DateOperator string
+
+// Query creates {field: {operator: date}}, or {field: date} for an empty operator.
+func (o DateOperator) Query(field string, value any) (map[string]any, error) {
+	date, err := toDate(value)
+	if err != nil {
+		return nil, err
+	}
+	return ComparisonOperator(o).Query(field, date)
+}
+
+// RegexOperator matches string values with a regular expression built from Format.
+// The value is quoted before it is applied to the format, so it is always matched literally.
+type RegexOperator struct {
+	Format  string // Format string to build the pattern, e.g. "^%s" to match a prefix.
+	Options string // Regex options, e.g. "i" for a case-insensitive match.
+	Negate  bool   // Negate the match with $not.
+}
+
+// Query creates {field: {"$regex": pattern}}, wrapped in {"$not": ...} if the operator is negated.
+func (o RegexOperator) Query(field string, value any) (map[string]any, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("regex value must be a string, got [%T]", value)
+	}
+
+	regex := map[string]any{"$regex": fmt.Sprintf(o.Format, regexp.QuoteMeta(str))}
+	if o.Options != "" {
+		regex["$options"] = o.Options
+	}
+	if o.Negate {
+		return map[string]any{field: map[string]any{"$not": regex}}, nil
+	}
+	return map[string]any{field: regex}, nil
+}
+
+// InOperator matches a field against a list of values with $in.
+type InOperator uint8
+
+// Query creates {field: {"$in": values}}.
+func (InOperator) Query(field string, value any) (map[string]any, error) {
+	values, ok := value.([]any)
+	if !ok {
+		values = []any{value}
+	}
+	return map[string]any{field: map[string]any{"$in": values}}, nil
+}
+
+// BetweenOperator matches a field within an inclusive range of two values with $gte and $lte.
+// String bounds that parse as RFC 3339 timestamps are compared as BSON dates, as with DateOperator,
+// so the same operator serves number and date ranges.
+type BetweenOperator uint8
+
+// Query creates {field: {"$gte": from, "$lte": to}}.
+func (BetweenOperator) Query(field string, value any) (map[string]any, error) {
+	values, ok := value.([]any)
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("between value must be a list of 2 values, got [%v]", value)
+	}
+	return map[string]any{field: map[string]any{"$gte": asDate(values[0]), "$lte": asDate(values[1])}}, nil
+}
+
+// DefaultOperators returns the operators for every filter.MatchMode, matching the semantics
+// of the SQL filters in the filters package.
+func DefaultOperators() map[filter.MatchMode]Operator {
+	return map[filter.MatchMode]Operator{
+		filter.EQUALS:              ComparisonOperator(""),
+		filter.NOT_EQUALS:          ComparisonOperator("$ne"),
+		filter.CONTAINS:            RegexOperator{Format: "%s"},
+		filter.NOT_CONTAINS:        RegexOperator{Format: "%s", Negate: true},
+		filter.STARTS_WITH:         RegexOperator{Format: "^%s"},
+		filter.ENDS_WITH:           RegexOperator{Format: "%s$"},
+		filter.LESS_THAN:           ComparisonOperator("$lt"),
+		filter.LESS_THAN_EQUALS:    ComparisonOperator("$lte"),
+		filter.GREATER_THAN:        ComparisonOperator("$gt"),
+		filter.GREATER_THAN_EQUALS: ComparisonOperator("$gte"),
+		filter.DATE_BEFORE:         DateOperator("$lt"),
+		filter.DATE_AFTER:          DateOperator("$gt"),
+		filter.DATE_IS:             DateOperator(""),
+		filter.DATE_IS_NOT:         DateOperator("$ne"),
+		filter.IN:                  InOperator(0),
+		filter.BETWEEN:             BetweenOperator(0),
+	}
+}
+
+// asDate returns a string value parsed as an RFC 3339 timestamp, or the value itself if it does not parse.
+func asDate(value any) any {
+	if date, err := toDate(value); err == nil {
+		return date
+	}
+	return value
+}
+
+func toDate(value any) (any, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	date, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date [%s]: %w", str, err)
+	}
+	return date, nil
+}
@@ -0,0 +1,114 @@
+package primepgx
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/prime"
+	"github.com/AdamShannag/goprime/primesql"
+	"github.com/jackc/pgx/v5"
+)
+
+// Batcher sends a batch of queries; it is implemented by *pgx.Conn, *pgxpool.Pool and pgx.Tx.
+type Batcher interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// Page runs the count and data queries of a lazy load event in a single pgx.Batch round trip
+// and scans the rows of the page into T with pgx.RowToStructByName.
+// See PageFunc for the queries that are run.
+// Parameters:
+//
+//	ctx: The context of the request, also passed to the mandatory predicates and context-aware filters.
+//	db: The connection, pool or transaction sending the batch.
+//	pf: The Filter generating the condition and ORDER BY list of the event, e.g. configured with WithPgx.
+//	base: The base query, a SELECT without WHERE, GROUP BY, ORDER BY or LIMIT clauses.
+//	event: The lazy load event of the table.
+//
+// Returns:
+//
+//	The Result, or an error if the event is invalid, a query fails or a row cannot be scanned.
+func Page[T any](ctx context.Context, db Batcher, pf prime.ReadOnlyFilter, base string, event prime.LazyLoadEvent) (primesql.Result[T], error) {
+	return PageFunc(ctx, db, pf, base, event, pgx.RowToStructByName[T])
+}
+
+// PageFunc runs the count and data queries of a lazy load event in a single pgx.Batch round trip
+// and scans the rows of the page with scan.
+// The queries are those of primesql.PageFunc: the condition generated for the filters is appended to the
+// base query as a WHERE clause, and the data query adds the ORDER BY list and LIMIT ... OFFSET clause.
+// Unlike primesql.PageFunc, the data query is sent even when nothing matches.
+// The values are bound as pgx.NamedArgs when the Filter uses the Named dialect.
+// Parameters:
+//
+//	ctx: The context of the request, also passed to the mandatory predicates and context-aware filters.
+//	db: The connection, pool or transaction sending the batch.
+//	pf: The Filter generating the condition and ORDER BY list of the event.
+//	base: The base query, a SELECT without WHERE, GROUP BY, ORDER BY or LIMIT clauses.
+//	event: The lazy load event of the table.
+//	scan: The function scanning a row into a T, e.g. pgx.RowToStructByPos[T].
+//
+// Returns:
+//
+//	The Result, or an error if the event is invalid, a query fails or a row cannot be scanned.
+func PageFunc[T any](ctx context.Context, db Batcher, pf prime.ReadOnlyFilter, base string, event prime.LazyLoadEvent, scan pgx.RowToFunc[T]) (primesql.Result[T], error) {
+	result := primesql.Result[T]{Data: []T{}}
+
+	batch, err := QueuePage(ctx, pf, base, event)
+	if err != nil {
+		return result, err
+	}
+
+	results := db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	if err = results.QueryRow().Scan(&result.TotalRecords); err != nil {
+		return result, fmt.Errorf("count query: %w", err)
+	}
+
+	rows, err := results.Query()
+	if err != nil {
+		return result, fmt.Errorf("data query: %w", err)
+	}
+
+	data, err := pgx.CollectRows(rows, scan)
+	if err != nil {
+		return result, err
+	}
+	if data != nil {
+		result.Data = data
+	}
+	return result, results.Close()
+}
+
+// QueuePage validates a lazy load event and queues its count query followed by its data query in a new pgx.Batch,
+// so they can be sent together with other queries.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the mandatory predicates and context-aware filters.
+//	pf: The Filter generating the condition and ORDER BY list of the event.
+//	base: The base query, a SELECT without WHERE, GROUP BY, ORDER BY or LIMIT clauses.
+//	event: The lazy load event of the table.
+//
+// Returns:
+//
+//	The batch, or an error if the event is invalid.
+func QueuePage(ctx context.Context, pf prime.ReadOnlyFilter, base string, event prime.LazyLoadEvent) (*pgx.Batch, error) {
+	vals, condition, err := pf.SqlContext(ctx, event.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy, err := pf.OrderBy(event.Sorts())
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := base
+	if condition != "" {
+		filtered += " WHERE " + condition
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue(primesql.CountQuery(filtered), args(pf, vals)...)
+	batch.Queue(filtered+primesql.PageClause(pf.Dialect().Name, orderBy, event.First, event.Rows), args(pf, vals)...)
+	return batch, nil
+}
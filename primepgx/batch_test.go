@@ -0,0 +1,170 @@
+package primepgx
+
+import (
+	"context"
+	"errors"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/prime"
+	"github.com/AdamShannag/goprime/primesql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"reflect"
+	"testing"
+)
+
+// fakeBatcher answers the count query with count and the data query with rows, and records the queued queries.
+type fakeBatcher struct {
+	count   int
+	columns []string
+	rows    [][]any
+	queued  []*pgx.QueuedQuery
+	sent    int
+}
+
+func (b *fakeBatcher) SendBatch(_ context.Context, batch *pgx.Batch) pgx.BatchResults {
+	b.sent++
+	b.queued = batch.QueuedQueries
+	return &fakeResults{batcher: b}
+}
+
+type fakeResults struct {
+	batcher *fakeBatcher
+	read    int
+}
+
+func (r *fakeResults) Exec() (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("not supported")
+}
+func (r *fakeResults) Close() error { return nil }
+
+func (r *fakeResults) QueryRow() pgx.Row {
+	r.read++
+	return &fakeRows{columns: []string{"count"}, rows: [][]any{{r.batcher.count}}}
+}
+
+func (r *fakeResults) Query() (pgx.Rows, error) {
+	r.read++
+	return &fakeRows{columns: r.batcher.columns, rows: r.batcher.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]any
+	current []any
+}
+
+func (r *fakeRows) Close()                        {}
+func (r *fakeRows) Err() error                    { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag { return pgconn.CommandTag{} }
+func (r *fakeRows) RawValues() [][]byte           { return nil }
+func (r *fakeRows) Conn() *pgx.Conn               { return nil }
+func (r *fakeRows) Values() ([]any, error)        { return r.current, nil }
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, len(r.columns))
+	for i, c := range r.columns {
+		fields[i] = pgconn.FieldDescription{Name: c}
+	}
+	return fields
+}
+
+func (r *fakeRows) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	r.current, r.rows = r.rows[0], r.rows[1:]
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if r.current == nil && !r.Next() {
+		return pgx.ErrNoRows
+	}
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.current[i]))
+	}
+	return nil
+}
+
+type customer struct {
+	ID   int64
+	Name string
+}
+
+func newFilter(opts ...prime.Option) *prime.Filter {
+	pf := prime.New(opts...)
+	pf.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+	return pf
+}
+
+func TestPage(t *testing.T) {
+	db := &fakeBatcher{count: 12, columns: []string{"id", "name"}, rows: [][]any{{int64(1), "James"}, {int64(2), "Jane"}}}
+	event := prime.LazyLoadEvent{
+		First:     10,
+		Rows:      2,
+		SortField: "name",
+		SortOrder: 1,
+		Filters:   prime.Specs{"name": {{Value: "Ja", MatchMode: filter.STARTS_WITH}}},
+	}
+
+	result, err := Page[customer](context.Background(), db, newFilter(WithPgx()), "SELECT id, name FROM customers", event)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := primesql.Result[customer]{Data: []customer{{1, "James"}, {2, "Jane"}}, TotalRecords: 12}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected result %+v, got %+v", expected, result)
+	}
+
+	if db.sent != 1 || len(db.queued) != 2 {
+		t.Fatalf("expected a single batch of two queries, got %d batches of %d queries", db.sent, len(db.queued))
+	}
+	expectedQueries := []string{
+		"SELECT COUNT(*) FROM (SELECT id, name FROM customers WHERE ((name LIKE $1))) prime_count",
+		"SELECT id, name FROM customers WHERE ((name LIKE $1)) ORDER BY name ASC LIMIT 2 OFFSET 10",
+	}
+	for i, q := range db.queued {
+		if q.SQL != expectedQueries[i] {
+			t.Errorf("expected query %s, got %s", expectedQueries[i], q.SQL)
+		}
+		if !reflect.DeepEqual(q.Arguments, []any{"Ja%"}) {
+			t.Errorf("expected arguments [Ja%%], got %v", q.Arguments)
+		}
+	}
+}
+
+func TestPageWithNamedArgs(t *testing.T) {
+	db := &fakeBatcher{}
+	event := prime.LazyLoadEvent{Filters: prime.Specs{"name": {{Value: "Ja", MatchMode: filter.STARTS_WITH}}}}
+
+	result, err := Page[customer](context.Background(), db, newFilter(WithNamedArgs()), "SELECT id, name FROM customers", event)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if result.Data == nil || len(result.Data) != 0 {
+		t.Errorf("expected an empty page, got %+v", result)
+	}
+
+	if expected := "SELECT id, name FROM customers WHERE ((name LIKE @p1))"; db.queued[1].SQL != expected {
+		t.Errorf("expected query %s, got %s", expected, db.queued[1].SQL)
+	}
+	if expected := []any{pgx.NamedArgs{"p1": "Ja%"}}; !reflect.DeepEqual(db.queued[0].Arguments, expected) {
+		t.Errorf("expected arguments %v, got %v", expected, db.queued[0].Arguments)
+	}
+}
+
+func TestQueuePageErrors(t *testing.T) {
+	pf := newFilter(WithPgx())
+
+	var sortErr *prime.SortError
+	if _, err := QueuePage(context.Background(), pf, "SELECT * FROM customers", prime.LazyLoadEvent{SortField: "name; DROP TABLE customers"}); !errors.As(err, &sortErr) {
+		t.Errorf("expected a *prime.SortError, got %v", err)
+	}
+
+	event := prime.LazyLoadEvent{Filters: prime.Specs{"name": {{Value: "Ja", MatchMode: filter.CONTAINS}}}}
+	if _, err := QueuePage(context.Background(), pf, "SELECT * FROM customers", event); err == nil {
+		t.Error("expected an error for a match mode that is not registered, got nil")
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/AdamShannag/goprime/primepgx
+
+go 1.23
+
+replace github.com/AdamShannag/goprime => ../
+
+require (
+	github.com/AdamShannag/goprime v0.0.0
+	github.com/jackc/pgx/v5 v5.7.2
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
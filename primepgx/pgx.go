@@ -0,0 +1,173 @@
+package primepgx
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/prime"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// NamedPlaceholder is the placeholder of conditions bound with pgx.NamedArgs: "@p1", "@p2", ...
+const NamedPlaceholder = placeholder.Numbered("@p")
+
+// Named is the dialect of PostgreSQL with the values of conditions bound by name, as with pgx.NamedArgs.
+var Named = dialect.Dialect{Name: dialect.Postgres.Name, Placeholder: NamedPlaceholder, MaxParameters: dialect.Postgres.MaxParameters}
+
+// AnyFilter renders filter.IN conditions for pgx as "(column = ANY($1))", binding the values as a single array.
+// The statement does not change with the number of values, so it can be prepared and cached once,
+// and long lists do not count against the limit of bound values of PostgreSQL.
+type AnyFilter uint8
+
+// Render creates the SQL condition and binds the values as a single array.
+// Parameters:
+//
+//	_: The context of the request (ignored).
+//	column: The name of the SQL column to filter.
+//	values: The values of the condition.
+//
+// Returns:
+//
+//	The SQL condition, e.g. "(status = ANY(?))", and a single []any holding the values.
+func (AnyFilter) Render(_ context.Context, column string, values []any) (string, []any, error) {
+	return fmt.Sprintf("(%s = ANY(?))", column), []any{slices.Clone(values)}, nil
+}
+
+// TimestamptzFilter wraps a filter comparing a date column, binding its values as pgtype.Timestamptz.
+// Values are time.Time or RFC 3339 strings, as sent by a PrimeNG date filter.
+type TimestamptzFilter struct {
+	Inner filter.Renderer // Filter rendering the condition
+}
+
+// Timestamptz wraps a filter so that its values are bound as pgtype.Timestamptz.
+// Parameters:
+//
+//	f: The filter rendering the condition, e.g. filter.AsRenderer(filters.LocalDateFilter(filter.DATE_IS)).
+//
+// Returns:
+//
+//	The wrapping filter.
+func Timestamptz(f filter.Renderer) TimestamptzFilter {
+	return TimestamptzFilter{Inner: f}
+}
+
+// Render creates the SQL condition of the inner filter and converts its values to pgtype.Timestamptz.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the inner filter.
+//	column: The name of the SQL column to filter.
+//	values: The values of the condition.
+//
+// Returns:
+//
+//	The SQL condition and its values, or an error if a value is not a date.
+func (f TimestamptzFilter) Render(ctx context.Context, column string, values []any) (string, []any, error) {
+	sql, args, err := f.Inner.Render(ctx, column, values)
+	if err != nil {
+		return "", nil, err
+	}
+	return timestamptz(sql, args)
+}
+
+// RenderValue creates the SQL condition of the inner filter for the value of a condition as the client sent it,
+// see filter.ValueRenderer, and converts its values to pgtype.Timestamptz.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the inner filter.
+//	column: The name of the SQL column to filter.
+//	value: The value of the condition; a list value is a []any.
+//
+// Returns:
+//
+//	The SQL condition and its values, or an error if a value is not a date.
+func (f TimestamptzFilter) RenderValue(ctx context.Context, column string, value any) (string, []any, error) {
+	sql, args, err := filter.RenderValue(ctx, f.Inner, column, value)
+	if err != nil {
+		return "", nil, err
+	}
+	return timestamptz(sql, args)
+}
+
+func timestamptz(sql string, args []any) (string, []any, error) {
+
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			converted[i] = pgtype.Timestamptz{Time: v, Valid: true}
+		case string:
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid date [%s]: %w", v, err)
+			}
+			converted[i] = pgtype.Timestamptz{Time: t, Valid: true}
+		case pgtype.Timestamptz:
+			converted[i] = v
+		default:
+			return "", nil, fmt.Errorf("invalid date [%v]", arg)
+		}
+	}
+	return sql, converted, nil
+}
+
+// Renderers returns the filters binding values the way pgx expects them:
+// filter.IN is rendered with AnyFilter, and the date match modes bind pgtype.Timestamptz values.
+// Other match modes are not included; register them on the Filter as usual.
+func Renderers() map[filter.MatchMode]filter.Renderer {
+	return map[filter.MatchMode]filter.Renderer{
+		filter.IN:          AnyFilter(0),
+		filter.DATE_IS:     Timestamptz(filter.AsRenderer(filters.DateIsFilter(0))),
+		filter.DATE_IS_NOT: Timestamptz(filter.AsRenderer(filters.DateIsNotFilter(0))),
+		filter.DATE_BEFORE: Timestamptz(filter.AsRenderer(filters.DateBeforeFilter(0))),
+		filter.DATE_AFTER:  Timestamptz(filter.AsRenderer(filters.DateAfterFilter(0))),
+	}
+}
+
+// WithPgx configures a Filter for pgx: it uses the dialect.Postgres dialect and the filters of Renderers.
+func WithPgx() prime.Option {
+	return func(f *prime.Filter) {
+		prime.WithDialect(dialect.Postgres)(f)
+		prime.WithRenderers(Renderers())(f)
+	}
+}
+
+// WithNamedArgs configures a Filter for pgx as WithPgx, with the values of conditions bound by name
+// with the Named dialect, so conditions can be combined with queries using pgx.NamedArgs.
+func WithNamedArgs() prime.Option {
+	return func(f *prime.Filter) {
+		WithPgx()(f)
+		prime.WithDialect(Named)(f)
+	}
+}
+
+// NamedArgs returns the values of a condition rendered with NamedPlaceholder as pgx.NamedArgs,
+// named "p1", "p2", ... Use maps.Copy to add them to the named arguments of the rest of the query.
+// Parameters:
+//
+//	vals: The values returned with the condition, e.g. by Filter.SqlContext.
+//
+// Returns:
+//
+//	The named arguments.
+func NamedArgs(vals []any) pgx.NamedArgs {
+	args := make(pgx.NamedArgs, len(vals))
+	for i, v := range vals {
+		args["p"+strconv.Itoa(i+1)] = v
+	}
+	return args
+}
+
+// args returns the arguments of a query binding vals, as pgx.NamedArgs for a Filter with the Named dialect.
+func args(pf prime.ReadOnlyFilter, vals []any) []any {
+	if pf.Dialect().Placeholder == NamedPlaceholder {
+		return []any{NamedArgs(vals)}
+	}
+	return vals
+}
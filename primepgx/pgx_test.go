@@ -0,0 +1,104 @@
+package primepgx
+
+import (
+	"context"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/prime"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithPgx(t *testing.T) {
+	pf := prime.New(WithPgx())
+	pf.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+
+	specs := prime.Specs{
+		"status": {{Value: []any{"open", "closed", "pending"}, MatchMode: filter.IN}},
+		"date":   {{Value: "2024-08-12T21:00:00Z", MatchMode: filter.DATE_AFTER}},
+		"name":   {{Value: "Ja", MatchMode: filter.STARTS_WITH}},
+	}
+	vals, condition, err := pf.Sql(specs)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expectedCondition := "((date > $1)) and ((name LIKE $2)) and ((status = ANY($3)))"
+	if condition != expectedCondition {
+		t.Errorf("expected condition %s, got %s", expectedCondition, condition)
+	}
+
+	expectedVals := []any{
+		pgtype.Timestamptz{Time: time.Date(2024, 8, 12, 21, 0, 0, 0, time.UTC), Valid: true},
+		"Ja%",
+		[]any{"open", "closed", "pending"},
+	}
+	if !reflect.DeepEqual(vals, expectedVals) {
+		t.Errorf("expected values %v, got %v", expectedVals, vals)
+	}
+}
+
+func TestValuesAreEncodable(t *testing.T) {
+	m := pgtype.NewMap()
+
+	if _, err := m.Encode(pgtype.TextArrayOID, pgtype.BinaryFormatCode, []any{"open", "closed"}, nil); err != nil {
+		t.Errorf("expected the IN list to be encodable as text[], got %v", err)
+	}
+	if _, err := m.Encode(pgtype.Int8ArrayOID, pgtype.BinaryFormatCode, []any{float64(1), float64(2)}, nil); err != nil {
+		t.Errorf("expected JSON numbers to be encodable as int8[], got %v", err)
+	}
+	if _, err := m.Encode(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, pgtype.Timestamptz{Time: time.Now(), Valid: true}, nil); err != nil {
+		t.Errorf("expected the date to be encodable as timestamptz, got %v", err)
+	}
+}
+
+func TestTimestamptz(t *testing.T) {
+	f := Timestamptz(filter.AsRenderer(filters.LocalDateFilter(filter.DATE_IS)))
+
+	sql, args, err := f.Render(context.Background(), "date", []any{"2024-08-12T21:00:00Z"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if sql != "(date >= ? AND date < ?)" {
+		t.Errorf("expected the condition of the inner filter, got %s", sql)
+	}
+	expected := []any{
+		pgtype.Timestamptz{Time: time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC), Valid: true},
+		pgtype.Timestamptz{Time: time.Date(2024, 8, 13, 0, 0, 0, 0, time.UTC), Valid: true},
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected values %v, got %v", expected, args)
+	}
+
+	if _, _, err = Timestamptz(filter.AsRenderer(filters.DateIsFilter(0))).Render(context.Background(), "date", []any{"yesterday"}); err == nil {
+		t.Error("expected an error for a value that is not a date, got nil")
+	}
+	if _, _, err = Timestamptz(filter.AsRenderer(filters.DateIsFilter(0))).Render(context.Background(), "date", []any{42}); err == nil {
+		t.Error("expected an error for a value that is not a date, got nil")
+	}
+}
+
+func TestWithNamedArgs(t *testing.T) {
+	pf := prime.New(WithNamedArgs())
+	pf.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+
+	vals, condition, err := pf.Sql(prime.Specs{
+		"name":   {{Value: "James", MatchMode: filter.EQUALS}},
+		"status": {{Value: []any{"open"}, MatchMode: filter.IN}},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if expected := "((name = @p1)) and ((status = ANY(@p2)))"; condition != expected {
+		t.Errorf("expected condition %s, got %s", expected, condition)
+	}
+
+	expected := pgx.NamedArgs{"p1": "James", "p2": []any{"open"}}
+	if args := NamedArgs(vals); !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected named args %v, got %v", expected, args)
+	}
+}
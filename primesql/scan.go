@@ -0,0 +1,119 @@
+package primesql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// fieldIndexes caches the indexes of the fields the columns of a result are scanned into, per type and columns.
+var fieldIndexes sync.Map
+
+type fieldsKey struct {
+	t       reflect.Type
+	columns string
+}
+
+// ScanStruct scans the current row of rows into a T.
+// When T is a struct, every column is scanned into the field named by its db tag, its json tag or,
+// ignoring case, its Go name; embedded structs are searched too, allocating embedded pointers as needed,
+// and the shallowest field wins when several have the same name. Fields can be pointers or sql.Null
+// types to receive NULL values. Any other T, including time.Time and sql.Scanner implementations,
+// is scanned from a single column.
+// Parameters:
+//
+//	rows: The rows, positioned on a row with rows.Next.
+//
+// Returns:
+//
+//	The scanned T, or an error if a column has no matching field or cannot be scanned.
+func ScanStruct[T any](rows *sql.Rows) (T, error) {
+	var item T
+	v := reflect.ValueOf(&item).Elem()
+	if _, isScanner := any(&item).(sql.Scanner); isScanner || v.Kind() != reflect.Struct || v.Type() == timeType {
+		return item, rows.Scan(&item)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return item, err
+	}
+
+	indexes, err := fieldsOf(v.Type(), columns)
+	if err != nil {
+		return item, err
+	}
+
+	dest := make([]any, len(indexes))
+	for i, index := range indexes {
+		field, err := fieldByIndex(v, index)
+		if err != nil {
+			return item, fmt.Errorf("column [%s]: %w", columns[i], err)
+		}
+		dest[i] = field.Addr().Interface()
+	}
+	return item, rows.Scan(dest...)
+}
+
+// fieldByIndex returns the nested field of v at index, allocating the nil embedded pointers it goes through.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot allocate unexported embedded %s", v.Type())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+func fieldsOf(t reflect.Type, columns []string) ([][]int, error) {
+	key := fieldsKey{t: t, columns: strings.Join(columns, ",")}
+	if indexes, ok := fieldIndexes.Load(key); ok {
+		return indexes.([][]int), nil
+	}
+
+	names := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("db"), ",")
+		if name == "" {
+			name, _, _ = strings.Cut(f.Tag.Get("json"), ",")
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		// the shallowest field wins, as with the promotion of Go fields
+		key := strings.ToLower(name)
+		if index, exists := names[key]; !exists || len(f.Index) < len(index) {
+			names[key] = f.Index
+		}
+	}
+
+	indexes := make([][]int, len(columns))
+	for i, column := range columns {
+		index, ok := names[strings.ToLower(column)]
+		if !ok {
+			return nil, fmt.Errorf("column [%s] has no field in %s", column, t)
+		}
+		indexes[i] = index
+	}
+
+	fieldIndexes.Store(key, indexes)
+	return indexes, nil
+}
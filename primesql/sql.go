@@ -0,0 +1,151 @@
+package primesql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/AdamShannag/goprime/prime"
+	"strings"
+)
+
+// Querier runs queries; it is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Result is a page of rows together with the number of rows matching the filters,
+// in the shape expected by a lazy PrimeNG table.
+type Result[T any] struct {
+	Data         []T `json:"data"`         // Rows of the page
+	TotalRecords int `json:"totalRecords"` // Number of rows matching the filters
+}
+
+// ScanFunc scans the current row of rows into a T.
+type ScanFunc[T any] func(rows *sql.Rows) (T, error)
+
+// Page runs the data and count queries of a lazy load event and scans the rows of the page into T,
+// matching the columns of the result to the fields of T by their db tag, their json tag or their name.
+// See PageFunc for the queries that are run.
+// Parameters:
+//
+//	ctx: The context of the request, also passed to the mandatory predicates and context-aware filters.
+//	db: The database or transaction to query.
+//	pf: The Filter generating the condition and ORDER BY list of the event.
+//	base: The base query, a SELECT without WHERE, GROUP BY, ORDER BY or LIMIT clauses.
+//	event: The lazy load event of the table.
+//
+// Returns:
+//
+//	The Result, or an error if the event is invalid, a query fails or a row cannot be scanned.
+func Page[T any](ctx context.Context, db Querier, pf prime.ReadOnlyFilter, base string, event prime.LazyLoadEvent) (Result[T], error) {
+	return PageFunc(ctx, db, pf, base, event, ScanStruct[T])
+}
+
+// PageFunc runs the data and count queries of a lazy load event and scans the rows of the page with scan.
+// The condition generated for the filters is appended to the base query as a WHERE clause,
+// followed by the ORDER BY list and the paging clause of the dialect of the Filter.
+// The count query counts the filtered base query; the data query is skipped when nothing matches.
+// Parameters:
+//
+//	ctx: The context of the request, also passed to the mandatory predicates and context-aware filters.
+//	db: The database or transaction to query.
+//	pf: The Filter generating the condition and ORDER BY list of the event.
+//	base: The base query, a SELECT without WHERE, GROUP BY, ORDER BY or LIMIT clauses.
+//	event: The lazy load event of the table.
+//	scan: The function scanning a row into a T.
+//
+// Returns:
+//
+//	The Result, or an error if the event is invalid, a query fails or a row cannot be scanned.
+func PageFunc[T any](ctx context.Context, db Querier, pf prime.ReadOnlyFilter, base string, event prime.LazyLoadEvent, scan ScanFunc[T]) (Result[T], error) {
+	result := Result[T]{Data: []T{}}
+
+	vals, condition, err := pf.SqlContext(ctx, event.Filters)
+	if err != nil {
+		return result, err
+	}
+
+	orderBy, err := pf.OrderBy(event.Sorts())
+	if err != nil {
+		return result, err
+	}
+
+	filtered := base
+	if condition != "" {
+		filtered += " WHERE " + condition
+	}
+
+	if err = db.QueryRowContext(ctx, CountQuery(filtered), vals...).Scan(&result.TotalRecords); err != nil {
+		return result, fmt.Errorf("count query: %w", err)
+	}
+	if result.TotalRecords == 0 {
+		return result, nil
+	}
+
+	rows, err := db.QueryContext(ctx, filtered+PageClause(pf.Dialect().Name, orderBy, event.First, event.Rows), vals...)
+	if err != nil {
+		return result, fmt.Errorf("data query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return result, err
+		}
+		result.Data = append(result.Data, item)
+	}
+	return result, rows.Err()
+}
+
+// CountQuery returns a query counting the rows of a query.
+func CountQuery(query string) string {
+	return "SELECT COUNT(*) FROM (" + query + ") prime_count"
+}
+
+// PageClause returns the ORDER BY and paging clauses of a page for a dialect, with a leading space.
+// SQL Server and Oracle use OFFSET ... FETCH, which requires an ORDER BY clause on SQL Server;
+// other dialects use LIMIT ... OFFSET.
+// Parameters:
+//
+//	dialect: The name of the dialect, e.g. dialect.SQLServer.Name; empty for LIMIT ... OFFSET.
+//	orderBy: The ORDER BY list, e.g. from Filter.OrderBy; may be empty.
+//	first: The index of the first row of the page.
+//	rows: The number of rows of the page; 0 for every row.
+//
+// Returns:
+//
+//	The clauses, empty if the page is neither sorted nor limited.
+func PageClause(dialect, orderBy string, first, rows int) string {
+	var b strings.Builder
+	offsetFetch := dialect == "sqlserver" || dialect == "oracle"
+
+	if orderBy != "" {
+		b.WriteString(" ORDER BY " + orderBy)
+	} else if dialect == "sqlserver" && (first > 0 || rows > 0) {
+		b.WriteString(" ORDER BY (SELECT NULL)")
+	}
+
+	switch {
+	case offsetFetch && (first > 0 || rows > 0):
+		fmt.Fprintf(&b, " OFFSET %d ROWS", max(first, 0))
+		if rows > 0 {
+			fmt.Fprintf(&b, " FETCH NEXT %d ROWS ONLY", rows)
+		}
+	case rows > 0:
+		fmt.Fprintf(&b, " LIMIT %d", rows)
+		if first > 0 {
+			fmt.Fprintf(&b, " OFFSET %d", first)
+		}
+	case first > 0 && dialect == "mysql":
+		// MySQL requires a LIMIT before an OFFSET
+		fmt.Fprintf(&b, " LIMIT 18446744073709551615 OFFSET %d", first)
+	case first > 0 && dialect == "sqlite":
+		// SQLite requires a LIMIT before an OFFSET, where -1 means no limit
+		fmt.Fprintf(&b, " LIMIT -1 OFFSET %d", first)
+	case first > 0:
+		fmt.Fprintf(&b, " OFFSET %d", first)
+	}
+	return b.String()
+}
@@ -0,0 +1,289 @@
+package primesql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/prime"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDriver answers COUNT queries with count and any other query with rows, and records the queries it runs.
+type fakeDriver struct {
+	mu      sync.Mutex
+	count   int64
+	columns []string
+	rows    [][]driver.Value
+	queries []string
+	args    [][]driver.NamedValue
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.queries = append(c.d.queries, query)
+	c.d.args = append(c.d.args, args)
+
+	if strings.HasPrefix(query, "SELECT COUNT(*)") {
+		return &fakeRows{columns: []string{"count"}, rows: [][]driver.Value{{c.d.count}}}, nil
+	}
+	return &fakeRows{columns: c.d.columns, rows: c.d.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func openFake(t *testing.T, d *fakeDriver) *sql.DB {
+	t.Helper()
+	name := "primesql-fake-" + t.Name()
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+type customer struct {
+	ID      int64   `db:"id"`
+	Name    string  `json:"name"`
+	Country *string // scanned from "country"
+}
+
+func newFilter(d dialect.Dialect) prime.ReadOnlyFilter {
+	b := prime.NewBuilder(prime.WithDialect(d))
+	b.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+	b.RegisterFilter(filter.IN, filters.InFilter(0))
+	b.RegisterColumns(column.Configs{
+		"name":    {Field: "name", Column: "c.name", Type: column.STRING, Sortable: true},
+		"country": {Field: "country", Column: "c.country", Type: column.STRING},
+	})
+	return b.Build()
+}
+
+func TestPage(t *testing.T) {
+	spain := "Spain"
+	d := &fakeDriver{
+		count:   42,
+		columns: []string{"id", "name", "country"},
+		rows: [][]driver.Value{
+			{int64(1), "James", "Spain"},
+			{int64(2), "Jane", nil},
+		},
+	}
+	db := openFake(t, d)
+
+	event := prime.LazyLoadEvent{
+		First:     10,
+		Rows:      2,
+		SortField: "name",
+		SortOrder: -1,
+		Filters: prime.Specs{
+			"name":    {{Value: "Ja", MatchMode: filter.STARTS_WITH}},
+			"country": {{Value: []any{"Spain", "Italy"}, MatchMode: filter.IN}},
+		},
+	}
+
+	result, err := Page[customer](context.Background(), db, newFilter(dialect.Postgres), "SELECT c.id, c.name, c.country FROM customers c", event)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := Result[customer]{
+		Data:         []customer{{ID: 1, Name: "James", Country: &spain}, {ID: 2, Name: "Jane"}},
+		TotalRecords: 42,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected result %+v, got %+v", expected, result)
+	}
+
+	where := " WHERE ((c.country IN ($1,$2))) and ((c.name LIKE $3))"
+	expectedQueries := []string{
+		"SELECT COUNT(*) FROM (SELECT c.id, c.name, c.country FROM customers c" + where + ") prime_count",
+		"SELECT c.id, c.name, c.country FROM customers c" + where + " ORDER BY c.name DESC LIMIT 2 OFFSET 10",
+	}
+	if !reflect.DeepEqual(d.queries, expectedQueries) {
+		t.Errorf("expected queries\n%q\ngot\n%q", expectedQueries, d.queries)
+	}
+	for _, args := range d.args {
+		if len(args) != 3 || args[2].Value != "Ja%" {
+			t.Errorf("expected the values of the filters, got %v", args)
+		}
+	}
+}
+
+func TestPageWithoutMatches(t *testing.T) {
+	d := &fakeDriver{count: 0}
+	db := openFake(t, d)
+
+	result, err := Page[customer](context.Background(), db, newFilter(dialect.Postgres), "SELECT id, name, country FROM customers c", prime.LazyLoadEvent{Rows: 10})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if result.TotalRecords != 0 || result.Data == nil || len(result.Data) != 0 {
+		t.Errorf("expected an empty page, got %+v", result)
+	}
+	if len(d.queries) != 1 {
+		t.Errorf("expected only the count query, got %q", d.queries)
+	}
+}
+
+func TestPageErrors(t *testing.T) {
+	d := &fakeDriver{count: 1, columns: []string{"id", "unknown"}, rows: [][]driver.Value{{int64(1), "x"}}}
+	db := openFake(t, d)
+	pf := newFilter(dialect.Postgres)
+	base := "SELECT * FROM customers c"
+
+	if _, err := Page[customer](context.Background(), db, pf, base, prime.LazyLoadEvent{}); err == nil {
+		t.Error("expected an error for a column without a field, got nil")
+	}
+
+	var sortErr *prime.SortError
+	if _, err := Page[customer](context.Background(), db, pf, base, prime.LazyLoadEvent{SortField: "country"}); !errors.As(err, &sortErr) {
+		t.Errorf("expected a *prime.SortError, got %v", err)
+	}
+
+	event := prime.LazyLoadEvent{Filters: prime.Specs{"password": {{Value: "x", MatchMode: filter.STARTS_WITH}}}}
+	if _, err := Page[customer](context.Background(), db, pf, base, event); err == nil {
+		t.Error("expected a validation error, got nil")
+	}
+}
+
+func TestPageFunc(t *testing.T) {
+	d := &fakeDriver{count: 2, columns: []string{"name"}, rows: [][]driver.Value{{"James"}, {"Jane"}}}
+	db := openFake(t, d)
+
+	result, err := PageFunc(context.Background(), db, newFilter(dialect.SQLServer), "SELECT name FROM customers c", prime.LazyLoadEvent{First: 5, Rows: 2},
+		func(rows *sql.Rows) (string, error) {
+			var name string
+			err := rows.Scan(&name)
+			return strings.ToUpper(name), err
+		})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(result.Data, []string{"JAMES", "JANE"}) {
+		t.Errorf("expected [JAMES JANE], got %v", result.Data)
+	}
+	if expected := "SELECT name FROM customers c ORDER BY (SELECT NULL) OFFSET 5 ROWS FETCH NEXT 2 ROWS ONLY"; d.queries[1] != expected {
+		t.Errorf("expected query %s, got %s", expected, d.queries[1])
+	}
+}
+
+type audit struct {
+	CreatedBy string `db:"created_by"`
+}
+
+type auditedCustomer struct {
+	*audit
+	Name string `db:"name"`
+}
+
+type Audit struct {
+	CreatedBy string `db:"created_by"`
+}
+
+type exportedAuditCustomer struct {
+	*Audit
+	Name string `db:"name"`
+}
+
+func TestScanStructEmbeddedPointer(t *testing.T) {
+	d := &fakeDriver{count: 1, columns: []string{"name", "created_by"}, rows: [][]driver.Value{{"James", "admin"}}}
+	db := openFake(t, d)
+
+	result, err := PageFunc(context.Background(), db, newFilter(dialect.Postgres), "SELECT name, created_by FROM customers c", prime.LazyLoadEvent{}, ScanStruct[exportedAuditCustomer])
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(result.Data) != 1 || result.Data[0].Name != "James" || result.Data[0].Audit == nil || result.Data[0].CreatedBy != "admin" {
+		t.Errorf("unexpected data %+v", result.Data)
+	}
+
+	d.rows = [][]driver.Value{{"James", "admin"}}
+	if _, err = PageFunc(context.Background(), db, newFilter(dialect.Postgres), "SELECT name, created_by FROM customers c", prime.LazyLoadEvent{}, ScanStruct[auditedCustomer]); err == nil {
+		t.Error("expected an error for an unexported embedded pointer, got nil")
+	}
+}
+
+type base struct {
+	BaseID int64 `db:"id"`
+}
+
+type shadowingCustomer struct {
+	ID int64 `db:"id"`
+	base
+}
+
+func TestScanStructPrefersShallowFields(t *testing.T) {
+	d := &fakeDriver{count: 1, columns: []string{"id"}, rows: [][]driver.Value{{int64(7)}}}
+	db := openFake(t, d)
+
+	result, err := PageFunc(context.Background(), db, newFilter(dialect.Postgres), "SELECT id FROM customers c", prime.LazyLoadEvent{}, ScanStruct[shadowingCustomer])
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(result.Data) != 1 || result.Data[0].ID != 7 || result.Data[0].BaseID != 0 {
+		t.Errorf("expected the outer ID to be scanned, got %+v", result.Data)
+	}
+}
+
+func TestPageClause(t *testing.T) {
+	tests := []struct {
+		dialect  string
+		orderBy  string
+		first    int
+		rows     int
+		expected string
+	}{
+		{"postgres", "", 0, 0, ""},
+		{"postgres", "name ASC", 0, 0, " ORDER BY name ASC"},
+		{"postgres", "name ASC", 20, 10, " ORDER BY name ASC LIMIT 10 OFFSET 20"},
+		{"postgres", "", 20, 0, " OFFSET 20"},
+		{"mysql", "", 20, 0, " LIMIT 18446744073709551615 OFFSET 20"},
+		{"sqlite", "", 20, 0, " LIMIT -1 OFFSET 20"},
+		{"sqlserver", "", 0, 10, " ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{"oracle", "name DESC", 20, 0, " ORDER BY name DESC OFFSET 20 ROWS"},
+	}
+
+	for _, test := range tests {
+		if clause := PageClause(test.dialect, test.orderBy, test.first, test.rows); clause != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.dialect, test.expected, clause)
+		}
+	}
+}
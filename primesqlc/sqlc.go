@@ -0,0 +1,182 @@
+package primesqlc
+
+import (
+	"context"
+	"fmt"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/prime"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Marker marks where the condition of a lazy load event is injected into a query generated by sqlc.
+// It is placed right after WHERE, in front of its condition, or of TRUE when the query has no other condition:
+//
+//	-- name: ListCustomers :many
+//	SELECT * FROM customers WHERE /* prime:where */ tenant_id = $1 ORDER BY name;
+const Marker = "/* prime:where */"
+
+// Where injects the condition of the Specs of a lazy load event into a query generated by sqlc, at its Marker.
+// The condition is ANDed with the rest of the WHERE clause, which is wrapped in parentheses so that an OR
+// following the marker cannot bypass the condition. With a numbered dialect its placeholders are numbered
+// after the arguments of the query; with an unnumbered dialect its values are inserted among the arguments
+// at the position of the marker. The query can then be run with the returned arguments from a method added
+// to the generated Queries:
+//
+//	func (q *Queries) ListCustomersFiltered(ctx context.Context, pf prime.ReadOnlyFilter, specs prime.Specs, tenantID int64) ([]Customer, error) {
+//		query, args, err := primesqlc.Where(ctx, pf, specs, dialect.Postgres, listCustomers, tenantID)
+//		if err != nil {
+//			return nil, err
+//		}
+//		rows, err := q.db.QueryContext(ctx, query, args...)
+//		...
+//	}
+//
+// Parameters:
+//
+//	ctx: The context of the request, passed to the mandatory predicates and context-aware filters.
+//	pf: The Filter generating the condition.
+//	specs: The Specs of the event.
+//	d: The dialect of the query, whose placeholder is used in the condition, e.g. dialect.Postgres.
+//	query: The query generated by sqlc, holding the Marker.
+//	args: The arguments of the query.
+//
+// Returns:
+//
+//	The query and its arguments with the values of the condition, or the error of the Filter,
+//	or an error if the query has no Marker or fewer arguments than placeholders before it.
+func Where(ctx context.Context, pf prime.ReadOnlyFilter, specs prime.Specs, d dialect.Dialect, query string, args ...any) (string, []any, error) {
+	at := strings.Index(query, Marker)
+	if at < 0 {
+		return "", nil, fmt.Errorf("primesqlc: the query has no %s marker", Marker)
+	}
+	vals, condition, err := pf.SqlContext(prime.WithMarkers(ctx), specs)
+	if err != nil {
+		return "", nil, err
+	}
+	// the marker and the space following it are replaced
+	before, rest := query[:at], strings.TrimPrefix(query[at+len(Marker):], " ")
+	if condition == "" {
+		return before + rest, args, nil
+	}
+
+	// values of unnumbered placeholders are bound in order, so they go after the arguments preceding the marker
+	position := len(args)
+	if !d.Placeholder.Numbered() {
+		position = placeholders(before, d)
+		if position > len(args) {
+			return "", nil, fmt.Errorf("primesqlc: the query has %d placeholders before the marker for %d arguments", position, len(args))
+		}
+	}
+
+	condition, err = filter.Bind(condition, vals, position+1, d.Placeholder)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if end := clauseEnd(rest, d); end > 0 {
+		query = before + "(" + condition + ") AND (" + rest[:end] + ")" + rest[end:]
+	} else {
+		query = before + "(" + condition + ") " + rest
+	}
+	return query, slices.Concat(args[:position], vals, args[position:]), nil
+}
+
+// clauseKeywords end the condition of a WHERE clause.
+var clauseKeywords = []string{"GROUP", "HAVING", "WINDOW", "ORDER", "LIMIT", "OFFSET", "FETCH", "FOR", "UNION", "INTERSECT", "EXCEPT", "RETURNING"}
+
+// clauseEnd returns the index following the condition at the start of a WHERE clause, which ends at the first
+// keyword of the next clause, the parenthesis closing a subquery, a semicolon or the end of the query.
+// Trailing spaces and comments are not part of the condition.
+func clauseEnd(query string, d dialect.Dialect) int {
+	depth, end := 0, 0
+	for i := 0; i < len(query); i++ {
+		if next := skip(query, i, d); next > i {
+			if query[i] != '-' && query[i] != '/' {
+				end = next
+			}
+			i = next - 1
+			continue
+		}
+
+		switch c := query[i]; {
+		case c == ')' && depth == 0, c == ';' && depth == 0:
+			return end
+		case depth == 0 && isWordStart(query, i) && isKeyword(query[i:]):
+			return end
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		}
+		if !unicode.IsSpace(rune(query[i])) {
+			end = i + 1
+		}
+	}
+	return end
+}
+
+// isKeyword reports whether a query starts with a keyword of clauseKeywords.
+func isKeyword(query string) bool {
+	for _, keyword := range clauseKeywords {
+		if len(query) >= len(keyword) && strings.EqualFold(query[:len(keyword)], keyword) &&
+			(len(query) == len(keyword) || !isWordByte(query[len(keyword)])) {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholders counts the placeholders of an unnumbered dialect in a query, outside strings, identifiers and comments.
+func placeholders(query string, d dialect.Dialect) int {
+	ph, n := d.Placeholder.Get(1), 0
+	for i := 0; i < len(query); i++ {
+		if end := skip(query, i, d); end > i {
+			i = end - 1
+			continue
+		}
+		if strings.HasPrefix(query[i:], ph) {
+			n++
+			i += len(ph) - 1
+		}
+	}
+	return n
+}
+
+// skip returns the index following the string, quoted identifier or comment starting at index i of a query,
+// or i if none starts there. MySQL strings can escape their quote with a backslash.
+func skip(query string, i int, d dialect.Dialect) int {
+	switch c := query[i]; {
+	case c == '\'' || c == '"' || c == '`':
+		for j := i + 1; j < len(query); j++ {
+			switch {
+			case query[j] == '\\' && c != '`' && d.Name == dialect.MySQL.Name:
+				j++
+			case query[j] == c:
+				return j + 1
+			}
+		}
+		return len(query)
+	case strings.HasPrefix(query[i:], "--"):
+		if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+			return i + end + 1
+		}
+		return len(query)
+	case strings.HasPrefix(query[i:], "/*"):
+		if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 2
+		}
+		return len(query)
+	}
+	return i
+}
+
+func isWordStart(query string, i int) bool {
+	return isWordByte(query[i]) && (i == 0 || !isWordByte(query[i-1]))
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
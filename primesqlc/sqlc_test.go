@@ -0,0 +1,132 @@
+package primesqlc
+
+import (
+	"context"
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/prime"
+	"reflect"
+	"testing"
+)
+
+const listCustomers = `-- name: ListCustomers :many
+SELECT id, name FROM customers
+WHERE /* prime:where */ tenant_id = $1
+ORDER BY name
+`
+
+func TestWhere(t *testing.T) {
+	// the Filter is rendered with markers, so its own placeholder does not matter
+	pf := prime.New(prime.WithDialect(dialect.Postgres), prime.WithFilters(map[filter.MatchMode]filter.Filter{
+		filter.STARTS_WITH: filters.NewPatternMatchFilter("LIKE", filters.POST),
+		filter.IN:          filters.InFilter(0),
+	}))
+	specs := prime.Specs{
+		"name":   {{Value: "Ja", MatchMode: filter.STARTS_WITH}},
+		"status": {{Value: []any{"open", "closed"}, MatchMode: filter.IN}},
+	}
+
+	query, args, err := Where(context.Background(), pf, specs, dialect.Postgres, listCustomers, int64(7))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := `-- name: ListCustomers :many
+SELECT id, name FROM customers
+WHERE (((name LIKE $2)) and ((status IN ($3,$4)))) AND (tenant_id = $1)
+ORDER BY name
+`
+	if query != expected {
+		t.Errorf("expected query\n%s\ngot\n%s", expected, query)
+	}
+	if expectedArgs := []any{int64(7), "Ja%", "open", "closed"}; !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args %v, got %v", expectedArgs, args)
+	}
+
+	query, _, err = Where(context.Background(), pf, specs, dialect.MySQL, "SELECT * FROM customers WHERE /* prime:where */ TRUE")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if expected = "SELECT * FROM customers WHERE (((name LIKE ?)) and ((status IN (?,?)))) AND (TRUE)"; query != expected {
+		t.Errorf("expected query %s, got %s", expected, query)
+	}
+}
+
+func TestWhereUnnumbered(t *testing.T) {
+	pf := prime.New()
+	pf.RegisterFilter(filter.STARTS_WITH, filters.NewPatternMatchFilter("LIKE", filters.POST))
+	specs := prime.Specs{"name": {{Value: "Ja", MatchMode: filter.STARTS_WITH}}}
+	query := "SELECT * FROM customers WHERE region = ? AND note <> '?' AND /* prime:where */ tenant_id = ? OR TRUE -- any tenant\nORDER BY name LIMIT ?"
+
+	query, args, err := Where(context.Background(), pf, specs, dialect.MySQL, query, "eu", int64(7), 10)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := "SELECT * FROM customers WHERE region = ? AND note <> '?' AND (((name LIKE ?))) AND (tenant_id = ? OR TRUE) -- any tenant\nORDER BY name LIMIT ?"
+	if query != expected {
+		t.Errorf("expected query %s, got %s", expected, query)
+	}
+	if expectedArgs := []any{"eu", "Ja%", int64(7), 10}; !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args %v, got %v", expectedArgs, args)
+	}
+
+	if _, _, err = Where(context.Background(), pf, specs, dialect.SQLite, "SELECT * FROM customers WHERE a = ? AND /* prime:where */ TRUE"); err == nil {
+		t.Error("expected an error for missing arguments, got nil")
+	}
+}
+
+func TestWhereKeepsLiteralQuestionMarks(t *testing.T) {
+	pf := prime.New()
+	pf.RegisterFilter(filter.CONTAINS, filters.ValueFilter("?"))
+	specs := prime.Specs{"name": {{Value: "x", MatchMode: filter.CONTAINS}}}
+
+	query, args, err := Where(context.Background(), pf, specs, dialect.Postgres, listCustomers, int64(7))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := `-- name: ListCustomers :many
+SELECT id, name FROM customers
+WHERE (((name ? $2))) AND (tenant_id = $1)
+ORDER BY name
+`
+	if query != expected || !reflect.DeepEqual(args, []any{int64(7), "x"}) {
+		t.Errorf("expected query\n%s\ngot\n%s %v", expected, query, args)
+	}
+}
+
+func TestWhereWithoutConditions(t *testing.T) {
+	query, args, err := Where(context.Background(), prime.New(), nil, dialect.Postgres, listCustomers, int64(7))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := `-- name: ListCustomers :many
+SELECT id, name FROM customers
+WHERE tenant_id = $1
+ORDER BY name
+`
+	if query != expected || !reflect.DeepEqual(args, []any{int64(7)}) {
+		t.Errorf("expected the query unchanged, got %s %v", query, args)
+	}
+}
+
+func TestWhereErrors(t *testing.T) {
+	pf := prime.New(prime.WithValidators(column.AllowedValidator{"name"}))
+	pf.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+
+	specs := prime.Specs{"name": {{Value: "James", MatchMode: filter.EQUALS}}}
+	if _, _, err := Where(context.Background(), pf, specs, dialect.Postgres, "SELECT * FROM customers"); err == nil {
+		t.Error("expected an error for a query without marker, got nil")
+	}
+
+	var validationErr *prime.ValidationError
+	specs = prime.Specs{"password": {{Value: "x", MatchMode: filter.EQUALS}}}
+	if _, _, err := Where(context.Background(), pf, specs, dialect.Postgres, listCustomers); !errors.As(err, &validationErr) {
+		t.Errorf("expected a *prime.ValidationError, got %v", err)
+	}
+}
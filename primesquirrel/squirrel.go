@@ -0,0 +1,75 @@
+package primesquirrel
+
+import (
+	"context"
+	"github.com/AdamShannag/goprime/expr"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/placeholder"
+	"github.com/AdamShannag/goprime/prime"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Condition is the condition generated by a prime.Filter for a filter tree, as a squirrel.Sqlizer
+// that can be passed to the Where method of a builder. It uses the "?" placeholder whatever the placeholder
+// of the Filter, and squirrel replaces it with the placeholder format of the builder:
+//
+//	sq.Select("*").From("customers").Where(primesquirrel.Where(ctx, pf, specs)).PlaceholderFormat(sq.Dollar)
+//
+// The condition is validated and generated when the query is built, so its errors are returned by ToSql.
+// It is wrapped in parentheses, so it can be combined with squirrel.Or and other conditions.
+// Without any condition to apply, it renders "(1=1)", as an empty squirrel.And.
+type Condition struct {
+	ctx    context.Context
+	filter prime.ReadOnlyFilter
+	node   expr.Node
+}
+
+var _ sq.Sqlizer = Condition{}
+
+// Where returns the condition of the Specs of a lazy load event.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the mandatory predicates and context-aware filters.
+//	pf: The Filter generating the condition.
+//	specs: The Specs of the event.
+//
+// Returns:
+//
+//	The Condition.
+func Where(ctx context.Context, pf prime.ReadOnlyFilter, specs prime.Specs) Condition {
+	return WhereExpr(ctx, pf, specs.Expr())
+}
+
+// WhereExpr returns the condition of a filter tree.
+// Parameters:
+//
+//	ctx: The context of the request, passed to the mandatory predicates and context-aware filters.
+//	pf: The Filter generating the condition.
+//	node: The root node of the filter tree.
+//
+// Returns:
+//
+//	The Condition.
+func WhereExpr(ctx context.Context, pf prime.ReadOnlyFilter, node expr.Node) Condition {
+	return Condition{ctx: ctx, filter: pf, node: node}
+}
+
+// ToSql validates the columns of the filter tree and generates its condition.
+// Returns:
+//
+//	The condition with "?" placeholders and its values, or the error of the Filter.
+func (c Condition) ToSql() (string, []any, error) {
+	vals, condition, err := c.filter.SqlExprContext(prime.WithMarkers(c.ctx), c.node)
+	if err != nil {
+		return "", nil, err
+	}
+	if condition == "" {
+		return "(1=1)", nil, nil
+	}
+
+	condition, err = filter.Bind(condition, vals, 1, placeholder.UnNumbered("?"))
+	if err != nil {
+		return "", nil, err
+	}
+	return "(" + condition + ")", vals, nil
+}
@@ -0,0 +1,66 @@
+package primesquirrel
+
+import (
+	"context"
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/dialect"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/filters"
+	"github.com/AdamShannag/goprime/prime"
+	sq "github.com/Masterminds/squirrel"
+	"reflect"
+	"testing"
+)
+
+func TestWhere(t *testing.T) {
+	// a Filter using the placeholder of another dialect can be shared with squirrel
+	pf := prime.New(prime.WithDialect(dialect.Postgres), prime.WithFilters(map[filter.MatchMode]filter.Filter{
+		filter.STARTS_WITH: filters.NewPatternMatchFilter("LIKE", filters.POST),
+		filter.IN:          filters.InFilter(0),
+	}))
+	specs := prime.Specs{
+		"name":   {{Value: "Ja", MatchMode: filter.STARTS_WITH}},
+		"status": {{Value: []any{"open", "closed"}, MatchMode: filter.IN}},
+	}
+
+	query, args, err := sq.Select("id", "name").
+		From("customers").
+		Where(sq.Eq{"tenant_id": 7}).
+		Where(Where(context.Background(), pf, specs)).
+		OrderBy("name").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := "SELECT id, name FROM customers WHERE tenant_id = $1 AND (((name LIKE $2)) and ((status IN ($3,$4)))) ORDER BY name"
+	if query != expected {
+		t.Errorf("expected query %s, got %s", expected, query)
+	}
+	if expectedArgs := []any{7, "Ja%", "open", "closed"}; !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestWhereWithoutConditions(t *testing.T) {
+	query, args, err := sq.Select("*").From("customers").Where(Where(context.Background(), prime.New(), nil)).ToSql()
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if query != "SELECT * FROM customers WHERE (1=1)" || len(args) != 0 {
+		t.Errorf("expected an always true condition, got %s %v", query, args)
+	}
+}
+
+func TestWhereErrors(t *testing.T) {
+	pf := prime.New(prime.WithValidators(column.AllowedValidator{"name"}))
+	pf.RegisterFilter(filter.EQUALS, filters.ValueFilter("="))
+
+	specs := prime.Specs{"password": {{Value: "x", MatchMode: filter.EQUALS}}}
+	var validationErr *prime.ValidationError
+	if _, _, err := sq.Select("*").From("customers").Where(Where(context.Background(), pf, specs)).ToSql(); !errors.As(err, &validationErr) {
+		t.Errorf("expected a *prime.ValidationError, got %v", err)
+	}
+}
@@ -0,0 +1,322 @@
+package primeurl
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/prime"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Codec encodes lazy load events into compact, bookmarkable query strings and decodes them back:
+//
+//	filter[name][startsWith]=Ja&filter[activity][between]=68,100&op[name]=or&sort=-date,name&first=20&rows=10&global=amy
+//
+// Each constraint is a filter[column][matchMode] parameter; a column has several constraints when it is repeated.
+// Values of list match modes are separated by commas, and commas and backslashes inside values
+// are escaped with a backslash. op[column] sets the operator of a column, "and" by default.
+// sort lists the sort fields, descending when prefixed with "-".
+// Constraints with a nil value are not encoded, and empty values are decoded as nil.
+//
+// The encoding is canonical: parameters are written in a fixed order, so equal events produce
+// equal query strings that can be cached.
+type Codec struct {
+	Columns   column.Configs     // Configuration of the fields, used to decode values with their type; values are strings without it
+	ListModes []filter.MatchMode // Match modes whose value is a list
+}
+
+// New creates a new Codec decoding the values of the configured columns with their type.
+// The list match modes are filter.IN and filter.BETWEEN.
+// Parameters:
+//
+//	columns: The configuration of the fields, e.g. derived with column.FromStruct; may be nil.
+//
+// Returns:
+//
+//	A pointer to a newly created Codec instance.
+func New(columns column.Configs) *Codec {
+	return &Codec{Columns: columns, ListModes: []filter.MatchMode{filter.IN, filter.BETWEEN}}
+}
+
+var defaultCodec = New(nil)
+
+// Encode encodes a lazy load event with a Codec without column configuration.
+func Encode(event prime.LazyLoadEvent) string {
+	return defaultCodec.Encode(event)
+}
+
+// Decode decodes a lazy load event with a Codec without column configuration, so every value is a string.
+func Decode(query string) (prime.LazyLoadEvent, error) {
+	return defaultCodec.Decode(query)
+}
+
+// Error is returned when a query string parameter cannot be decoded.
+type Error struct {
+	Param string // Name of the parameter
+	Err   error  // Error encountered while decoding the parameter
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid query parameter [%s]: %s", e.Param, e.Err.Error())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Encode encodes the filters, sort order, paging and global filter of a lazy load event.
+// Parameters:
+//
+//	event: The lazy load event to encode.
+//
+// Returns:
+//
+//	The query string, without a leading "?".
+func (c *Codec) Encode(event prime.LazyLoadEvent) string {
+	var params []string
+	add := func(key, value string) {
+		key = strings.NewReplacer("%5B", "[", "%5D", "]").Replace(url.QueryEscape(key))
+		value = strings.ReplaceAll(url.QueryEscape(value), "%2C", ",")
+		params = append(params, key+"="+value)
+	}
+
+	for _, col := range slices.Sorted(maps.Keys(event.Filters)) {
+		specs := event.Filters[col]
+		for _, s := range specs {
+			if s.Value != nil {
+				add("filter["+col+"]["+string(s.MatchMode)+"]", c.encodeValue(s.Value))
+			}
+		}
+		if len(specs) > 0 && strings.EqualFold(specs[0].Operator, "or") {
+			add("op["+col+"]", "or")
+		}
+	}
+
+	if sorts := event.Sorts(); len(sorts) > 0 {
+		fields := make([]string, len(sorts))
+		for i, s := range sorts {
+			fields[i] = s.Field
+			if s.Order < 0 {
+				fields[i] = "-" + s.Field
+			}
+		}
+		add("sort", strings.Join(fields, ","))
+	}
+
+	if event.First > 0 {
+		add("first", strconv.Itoa(event.First))
+	}
+	if event.Rows > 0 {
+		add("rows", strconv.Itoa(event.Rows))
+	}
+	if global := event.Global(); global != "" {
+		add("global", global)
+	}
+	return strings.Join(params, "&")
+}
+
+// Decode decodes a lazy load event from a query string produced by Encode.
+// Constraints keep the order of their parameters. Unknown parameters are ignored.
+// Parameters:
+//
+//	query: The query string, with or without a leading "?".
+//
+// Returns:
+//
+//	The decoded event, or an *Error if a parameter is malformed.
+func (c *Codec) Decode(query string) (prime.LazyLoadEvent, error) {
+	var event prime.LazyLoadEvent
+	operators := make(map[string]string)
+
+	for _, param := range strings.Split(strings.TrimPrefix(query, "?"), "&") {
+		if param == "" {
+			continue
+		}
+		rawKey, rawValue, _ := strings.Cut(param, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			return event, &Error{Param: rawKey, Err: err}
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			return event, &Error{Param: key, Err: err}
+		}
+
+		if err = c.decodeParam(&event, operators, key, value); err != nil {
+			return event, &Error{Param: key, Err: err}
+		}
+	}
+
+	for col, op := range operators {
+		for i := range event.Filters[col] {
+			event.Filters[col][i].Operator = op
+		}
+	}
+	return event, nil
+}
+
+func (c *Codec) decodeParam(event *prime.LazyLoadEvent, operators map[string]string, key, value string) error {
+	switch {
+	case strings.HasPrefix(key, "filter["):
+		col, mode, ok := brackets(strings.TrimPrefix(key, "filter"), 2)
+		if !ok {
+			return fmt.Errorf("expected filter[column][matchMode]")
+		}
+		v, err := c.decodeValue(col, filter.MatchMode(mode), value)
+		if err != nil {
+			return err
+		}
+		if event.Filters == nil {
+			event.Filters = make(prime.Specs)
+		}
+		event.Filters[col] = append(event.Filters[col], filter.Spec{Value: v, MatchMode: filter.MatchMode(mode), Operator: "and"})
+	case strings.HasPrefix(key, "op["):
+		col, _, ok := brackets(strings.TrimPrefix(key, "op"), 1)
+		if !ok {
+			return fmt.Errorf("expected op[column]")
+		}
+		op := strings.ToLower(value)
+		if op != "and" && op != "or" {
+			return fmt.Errorf("unknown operator [%s]", value)
+		}
+		operators[col] = op
+	case key == "sort":
+		var sorts []prime.SortMeta
+		for _, field := range strings.Split(value, ",") {
+			if field == "" {
+				continue
+			}
+			if desc, ok := strings.CutPrefix(field, "-"); ok {
+				sorts = append(sorts, prime.SortMeta{Field: desc, Order: -1})
+			} else {
+				sorts = append(sorts, prime.SortMeta{Field: field, Order: 1})
+			}
+		}
+		if len(sorts) == 1 {
+			event.SortField, event.SortOrder = sorts[0].Field, sorts[0].Order
+		} else {
+			event.MultiSortMeta = sorts
+		}
+	case key == "first", key == "rows":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		if n < 0 {
+			return fmt.Errorf("must not be negative")
+		}
+		if key == "first" {
+			event.First = n
+		} else {
+			event.Rows = n
+		}
+	case key == "global":
+		event.GlobalFilter = value
+	}
+	return nil
+}
+
+// brackets splits "[a][b]" into its n bracketed parts.
+func brackets(s string, n int) (string, string, bool) {
+	var parts []string
+	for len(s) > 0 {
+		if s[0] != '[' {
+			return "", "", false
+		}
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return "", "", false
+		}
+		parts = append(parts, s[1:end])
+		s = s[end+1:]
+	}
+	if len(parts) != n || slices.Contains(parts, "") {
+		return "", "", false
+	}
+	if n == 1 {
+		return parts[0], "", true
+	}
+	return parts[0], parts[1], true
+}
+
+func (c *Codec) encodeValue(value any) string {
+	values, ok := value.([]any)
+	if !ok {
+		return formatValue(value)
+	}
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = strings.NewReplacer(`\`, `\\`, `,`, `\,`).Replace(formatValue(v))
+	}
+	return strings.Join(escaped, ",")
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (c *Codec) decodeValue(col string, mode filter.MatchMode, value string) (any, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if !slices.Contains(c.ListModes, mode) {
+		return c.parseValue(col, value)
+	}
+
+	var values []any
+	for _, element := range splitList(value) {
+		v, err := c.parseValue(col, element)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// parseValue converts a value to the type of its column, as it would be decoded from JSON.
+func (c *Codec) parseValue(col, value string) (any, error) {
+	switch c.Columns[col].Type {
+	case column.NUMBER:
+		return strconv.ParseFloat(value, 64)
+	case column.BOOLEAN:
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// splitList splits a list on unescaped commas and unescapes its elements.
+func splitList(value string) []string {
+	var (
+		elements []string
+		current  strings.Builder
+	)
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			i++
+			current.WriteByte(value[i])
+		case value[i] == ',':
+			elements = append(elements, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(value[i])
+		}
+	}
+	return append(elements, current.String())
+}
@@ -0,0 +1,113 @@
+package primeurl
+
+import (
+	"errors"
+	"github.com/AdamShannag/goprime/column"
+	"github.com/AdamShannag/goprime/filter"
+	"github.com/AdamShannag/goprime/prime"
+	"reflect"
+	"testing"
+)
+
+func newEvent() prime.LazyLoadEvent {
+	return prime.LazyLoadEvent{
+		First: 20,
+		Rows:  10,
+		MultiSortMeta: []prime.SortMeta{
+			{Field: "date", Order: -1},
+			{Field: "name", Order: 1},
+		},
+		Filters: prime.Specs{
+			"name": {
+				{Value: "Ja", MatchMode: filter.STARTS_WITH, Operator: "or"},
+				{Value: "Bob & Amy", MatchMode: filter.EQUALS, Operator: "or"},
+			},
+			"representative": {
+				{Value: []any{"Elsner, Amy", `Back\slash`}, MatchMode: filter.IN, Operator: "and"},
+			},
+			"activity": {
+				{Value: []any{68.0, 100.0}, MatchMode: filter.BETWEEN, Operator: "and"},
+			},
+			"country.name": {
+				{Value: nil, MatchMode: filter.STARTS_WITH, Operator: "and"},
+			},
+		},
+		GlobalFilter: "amy",
+	}
+}
+
+func TestEncode(t *testing.T) {
+	expected := "filter[activity][between]=68,100" +
+		"&filter[name][startsWith]=Ja&filter[name][equals]=Bob+%26+Amy&op[name]=or" +
+		"&filter[representative][in]=Elsner%5C,+Amy,Back%5C%5Cslash" +
+		"&sort=-date,name&first=20&rows=10&global=amy"
+
+	if query := Encode(newEvent()); query != expected {
+		t.Errorf("expected query\n%s\ngot\n%s", expected, query)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	codec := New(column.Configs{"activity": {Field: "activity", Type: column.NUMBER}})
+
+	event, err := codec.Decode("?" + codec.Encode(newEvent()))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := newEvent()
+	delete(expected.Filters, "country.name")
+	if !reflect.DeepEqual(event, expected) {
+		t.Errorf("expected event\n%+v\ngot\n%+v", expected, event)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	codec := New(column.Configs{
+		"activity": {Field: "activity", Type: column.NUMBER},
+		"verified": {Field: "verified", Type: column.BOOLEAN},
+	})
+
+	event, err := codec.Decode("filter[name][startsWith]=Ja&filter[activity][between]=68,100&filter[verified][equals]=true&filter[date][dateIs]=&sort=-date&unknown=1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	expected := prime.LazyLoadEvent{
+		SortField: "date",
+		SortOrder: -1,
+		Filters: prime.Specs{
+			"name":     {{Value: "Ja", MatchMode: filter.STARTS_WITH, Operator: "and"}},
+			"activity": {{Value: []any{68.0, 100.0}, MatchMode: filter.BETWEEN, Operator: "and"}},
+			"verified": {{Value: true, MatchMode: filter.EQUALS, Operator: "and"}},
+			"date":     {{Value: nil, MatchMode: filter.DATE_IS, Operator: "and"}},
+		},
+	}
+	if !reflect.DeepEqual(event, expected) {
+		t.Errorf("expected event\n%+v\ngot\n%+v", expected, event)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	codec := New(column.Configs{"activity": {Field: "activity", Type: column.NUMBER}})
+
+	tests := map[string]string{
+		"filter[name]=Ja":                "filter[name]",
+		"filter[name][startsWith][x]=Ja": "filter[name][startsWith][x]",
+		"filter[activity][gt]=many":      "filter[activity][gt]",
+		"op[name]=xor":                   "op[name]",
+		"rows=-1":                        "rows",
+		"first=ten":                      "first",
+		"filter[name][startsWith]=%zz":   "filter[name][startsWith]",
+	}
+
+	for query, param := range tests {
+		t.Run(query, func(t *testing.T) {
+			_, err := codec.Decode(query)
+			var urlErr *Error
+			if !errors.As(err, &urlErr) || urlErr.Param != param {
+				t.Errorf("expected an *Error on %s, got %v", param, err)
+			}
+		})
+	}
+}
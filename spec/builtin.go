@@ -0,0 +1,89 @@
+package spec
+
+import (
+	"fmt"
+	"github.com/AdamShannag/goprime/filter"
+	"reflect"
+	"unicode/utf8"
+)
+
+// MinLengthValidator requires string values to have at least the given number of characters.
+// Values that are not strings are not checked.
+type MinLengthValidator int
+
+func (m MinLengthValidator) Validate(column string, _ filter.Spec, values []any) error {
+	for _, v := range values {
+		if s, ok := v.(string); ok && utf8.RuneCountInString(s) < int(m) {
+			return fmt.Errorf("value [%s] of column [%s] is shorter than %d characters", s, column, m)
+		}
+	}
+	return nil
+}
+
+func (MinLengthValidator) Name() string {
+	return "MinLengthValidator"
+}
+
+// MaxLengthValidator requires string values to have at most the given number of characters.
+// Values that are not strings are not checked.
+type MaxLengthValidator int
+
+func (m MaxLengthValidator) Validate(column string, _ filter.Spec, values []any) error {
+	for _, v := range values {
+		if s, ok := v.(string); ok && utf8.RuneCountInString(s) > int(m) {
+			return fmt.Errorf("value of column [%s] is longer than %d characters", column, m)
+		}
+	}
+	return nil
+}
+
+func (MaxLengthValidator) Name() string {
+	return "MaxLengthValidator"
+}
+
+// MaxItemsValidator limits the number of values of a constraint, e.g. the size of an "in" list.
+type MaxItemsValidator int
+
+func (m MaxItemsValidator) Validate(column string, _ filter.Spec, values []any) error {
+	if len(values) > int(m) {
+		return fmt.Errorf("column [%s] has %d values, more than the maximum of %d", column, len(values), m)
+	}
+	return nil
+}
+
+func (MaxItemsValidator) Name() string {
+	return "MaxItemsValidator"
+}
+
+// RangeValidator requires numeric values to be within an inclusive range.
+// Values that are not numbers are not checked.
+type RangeValidator struct {
+	Min float64 // Smallest allowed value
+	Max float64 // Largest allowed value
+}
+
+func (r RangeValidator) Validate(column string, _ filter.Spec, values []any) error {
+	for _, v := range values {
+		if n, ok := number(v); ok && (n < r.Min || n > r.Max) {
+			return fmt.Errorf("value [%v] of column [%s] is out of range [%v, %v]", v, column, r.Min, r.Max)
+		}
+	}
+	return nil
+}
+
+func (RangeValidator) Name() string {
+	return "RangeValidator"
+}
+
+func number(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
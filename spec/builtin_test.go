@@ -0,0 +1,88 @@
+package spec
+
+import (
+	"github.com/AdamShannag/goprime/filter"
+	"testing"
+)
+
+func TestBuiltinValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		values    []any
+		valid     bool
+	}{
+		{"min length valid", MinLengthValidator(3), []any{"Jam"}, true},
+		{"min length invalid", MinLengthValidator(3), []any{"Ja"}, false},
+		{"min length counts characters", MinLengthValidator(3), []any{"Ñoñ"}, true},
+		{"min length ignores numbers", MinLengthValidator(3), []any{1}, true},
+		{"max length valid", MaxLengthValidator(5), []any{"James"}, true},
+		{"max length invalid", MaxLengthValidator(5), []any{"Jameson"}, false},
+		{"max items valid", MaxItemsValidator(2), []any{"a", "b"}, true},
+		{"max items invalid", MaxItemsValidator(2), []any{"a", "b", "c"}, false},
+		{"range valid", RangeValidator{Min: 0, Max: 100}, []any{float64(0), 100}, true},
+		{"range invalid", RangeValidator{Min: 0, Max: 100}, []any{68, float64(101)}, false},
+		{"range ignores strings", RangeValidator{Min: 0, Max: 100}, []any{"1000"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.validator.Validate("name", filter.Spec{Value: test.values}, test.values)
+			if test.valid && err != nil {
+				t.Errorf("expected nil error, got %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestBuiltinValidatorNames(t *testing.T) {
+	tests := []struct {
+		validator Validator
+		expected  string
+	}{
+		{MinLengthValidator(1), "MinLengthValidator"},
+		{MaxLengthValidator(1), "MaxLengthValidator"},
+		{MaxItemsValidator(1), "MaxItemsValidator"},
+		{RangeValidator{}, "RangeValidator"},
+		{ScopedValidator{Validator: MaxItemsValidator(1)}, "MaxItemsValidator"},
+	}
+
+	for _, test := range tests {
+		if test.validator.Name() != test.expected {
+			t.Errorf("expected validator name %s, got %s", test.expected, test.validator.Name())
+		}
+	}
+}
+
+func TestScopedValidator(t *testing.T) {
+	validator := ScopedValidator{
+		Validator: MaxItemsValidator(2),
+		Columns:   []string{"status"},
+		Modes:     []filter.MatchMode{filter.IN},
+	}
+
+	values := []any{"open", "closed", "pending"}
+
+	tests := []struct {
+		name      string
+		column    string
+		matchMode filter.MatchMode
+		valid     bool
+	}{
+		{"in scope", "status", filter.IN, false},
+		{"other column", "owner", filter.IN, true},
+		{"other match mode", "status", filter.EQUALS, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validator.Validate(test.column, filter.Spec{Value: values, MatchMode: test.matchMode}, values)
+			if test.valid != (err == nil) {
+				t.Errorf("expected valid %v, got error %v", test.valid, err)
+			}
+		})
+	}
+}
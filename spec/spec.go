@@ -0,0 +1,54 @@
+package spec
+
+import (
+	"github.com/AdamShannag/goprime/filter"
+	"iter"
+	"slices"
+)
+
+type Validators []Validator
+
+// Validator validates a single filter constraint, with access to its column, match mode and values.
+type Validator interface {
+	// Validate checks a constraint.
+	// Parameters:
+	//   column: The column the constraint applies to.
+	//   spec: The constraint as sent by the client.
+	//   values: The values of the constraint; a list value is flattened into its elements.
+	// Returns:
+	//   An error if the constraint is invalid; otherwise, nil.
+	Validate(column string, spec filter.Spec, values []any) error
+	Name() string
+}
+
+func (s Validators) Iter(column string, spec filter.Spec, values []any) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for _, v := range s {
+			if !yield(v.Name(), v.Validate(column, spec, values)) {
+				return
+			}
+		}
+	}
+}
+
+// ScopedValidator applies a Validator only to some columns and match modes,
+// e.g. to limit the number of values of "in" on the "status" column.
+type ScopedValidator struct {
+	Validator Validator          // Validator to apply
+	Columns   []string           // Columns to validate; empty validates every column
+	Modes     []filter.MatchMode // Match modes to validate; empty validates every match mode
+}
+
+func (s ScopedValidator) Validate(column string, spec filter.Spec, values []any) error {
+	if len(s.Columns) > 0 && !slices.Contains(s.Columns, column) {
+		return nil
+	}
+	if len(s.Modes) > 0 && !slices.Contains(s.Modes, spec.MatchMode) {
+		return nil
+	}
+	return s.Validator.Validate(column, spec, values)
+}
+
+func (s ScopedValidator) Name() string {
+	return s.Validator.Name()
+}